@@ -0,0 +1,204 @@
+package gosss
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+)
+
+const (
+	// envelopeMagic is the first byte of every envelope encoded in binary,
+	// followed by the version of the format.
+	envelopeMagic = 0x45
+	// envelopeVersion is the current version of the binary format of the
+	// envelopes.
+	envelopeVersion = 0x01
+	// blobKeyLen is the length in bytes of the AES-256 keys used to encrypt
+	// the blobs.
+	blobKeyLen = 32
+	// envelopeNonceLen is the length in bytes of the nonces of the envelopes,
+	// which is the standard nonce size of AES-GCM.
+	envelopeNonceLen = 12
+)
+
+// Envelope struct contains an encrypted blob, generated with HideBlob. It
+// includes the identifier of the set of key shares it belongs to, the nonce
+// and the ciphertext, which includes the authentication tag of AES-GCM. The
+// identifier of the set is authenticated with the ciphertext, so the envelope
+// can only be decrypted with the key recovered from the same set of shares.
+// It implements the encoding.BinaryMarshaler interface and its unmarshaler
+// counterpart, so it can be stored and transmitted next to the shares.
+type Envelope struct {
+	SetID      []byte
+	Nonce      []byte
+	Ciphertext []byte
+}
+
+// MarshalBinary encodes the envelope with the magic byte and the version of
+// the format, followed by the set identifier and the nonce, which have a fixed
+// length, and the ciphertext:
+//
+//	magic | version | setID | nonce | ciphertext
+//
+// It returns an error if the identifier or the nonce have a wrong length. It
+// implements the encoding.BinaryMarshaler interface.
+func (e *Envelope) MarshalBinary() ([]byte, error) {
+	if len(e.SetID) != setIDLen || len(e.Nonce) != envelopeNonceLen {
+		return nil, ErrInvalidEnvelope
+	}
+	b := []byte{envelopeMagic, envelopeVersion}
+	b = append(b, e.SetID...)
+	b = append(b, e.Nonce...)
+	return append(b, e.Ciphertext...), nil
+}
+
+// UnmarshalBinary decodes an envelope encoded with MarshalBinary. It returns
+// an error if the magic byte does not match, if the version is not supported
+// or if the envelope is too short. It implements the
+// encoding.BinaryUnmarshaler interface.
+func (e *Envelope) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 || data[0] != envelopeMagic {
+		return ErrInvalidEnvelope
+	}
+	if data[1] != envelopeVersion {
+		return ErrUnsupportedVersion
+	}
+	r := &shareReader{b: data[2:]}
+	decoded := Envelope{
+		SetID: bytes.Clone(r.next(setIDLen)),
+		Nonce: bytes.Clone(r.next(envelopeNonceLen)),
+	}
+	if r.err {
+		return ErrInvalidEnvelope
+	}
+	decoded.Ciphertext = bytes.Clone(r.b)
+	*e = decoded
+	return nil
+}
+
+// HideBlob encrypts the data provided with a fresh AES-256-GCM key and hides
+// the key with the Shamir Secret Sharing algorithm, instead of the data
+// itself, so the size of the shares does not depend on the size of the data.
+// It returns the envelope with the ciphertext, which can be stored in a
+// single place, and the key shares as strings, encoded with their text
+// representation or as mnemonics, like HideMessage does, depending on the
+// encoding of the configuration. It uses the configuration provided to hide
+// the key, so it returns the same errors as HideMessage, or an error if the
+// data cannot be encrypted.
+func HideBlob(data []byte, conf *Config) (*Envelope, []string, error) {
+	key, shares, err := hideBlobKey(conf)
+	if err != nil {
+		return nil, nil, err
+	}
+	envelope := &Envelope{SetID: shares[0].SetID}
+	if envelope.Nonce, envelope.Ciphertext, err = encryptBlob(key, envelope.SetID, data); err != nil {
+		return nil, nil, err
+	}
+	strShares := make([]string, len(shares))
+	for i, share := range shares {
+		b, err := share.MarshalBinary()
+		if err != nil {
+			return nil, nil, err
+		}
+		strShares[i] = encodeShareText(b, conf.Encoding)
+	}
+	return envelope, strShares, nil
+}
+
+// RecoverBlob recovers the key from the shares provided as strings and
+// decrypts the data of the envelope provided with it. It uses RecoverMessage
+// to recover the key, so it returns the same errors. It returns an error if
+// the shares do not belong to the set of the envelope, or if the ciphertext
+// cannot be authenticated, because it was modified or the key recovered is
+// wrong.
+func RecoverBlob(envelope *Envelope, inputs []string, conf *Config) ([]byte, error) {
+	if envelope == nil {
+		return nil, ErrInvalidEnvelope
+	}
+	shares := make([]Share, len(inputs))
+	for i, input := range inputs {
+		if err := shares[i].UnmarshalText([]byte(input)); err != nil {
+			return nil, &ShareError{Position: i, Err: err}
+		}
+	}
+	if len(shares) > 0 && !bytes.Equal(shares[0].SetID, envelope.SetID) {
+		return nil, ErrShareSetMismatch
+	}
+	if len(envelope.Nonce) != envelopeNonceLen {
+		return nil, ErrInvalidEnvelope
+	}
+	key, err := recoverBlobKey(shares, conf)
+	if err != nil {
+		return nil, err
+	}
+	return decryptBlob(key, envelope.SetID, envelope.Nonce, envelope.Ciphertext)
+}
+
+// hideBlobKey generates a fresh AES-256 key and hides it with the
+// configuration provided. It returns the key and its shares, or an error if
+// the key cannot be generated or hidden.
+func hideBlobKey(conf *Config) ([]byte, []Share, error) {
+	key := make([]byte, blobKeyLen)
+	if _, err := rand.Read(key); err != nil {
+		return nil, nil, errors.Join(ErrReadingRandom, err)
+	}
+	shares, err := HideMessageShares(key, conf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, shares, nil
+}
+
+// recoverBlobKey recovers the AES-256 key from the shares provided. It returns
+// the errors of RecoverMessageShares, except if the recovered key cannot be
+// decoded or has a wrong length, which means that some share is wrong, so it
+// returns an authentication error.
+func recoverBlobKey(shares []Share, conf *Config) ([]byte, error) {
+	key, err := RecoverMessageShares(shares, conf)
+	if err == ErrDecodingMessage || (err == nil && len(key) != blobKeyLen) {
+		return nil, ErrBlobAuthentication
+	}
+	return key, err
+}
+
+// encryptBlob encrypts the data provided with the key provided using AES-GCM
+// and a random nonce, authenticating the set identifier provided with it. It
+// returns the nonce and the ciphertext, or an error if the data cannot be
+// encrypted.
+func encryptBlob(key, setID, data []byte) ([]byte, []byte, error) {
+	aead, err := blobCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, envelopeNonceLen)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, errors.Join(ErrReadingRandom, err)
+	}
+	return nonce, aead.Seal(nil, nonce, data, setID), nil
+}
+
+// decryptBlob decrypts the ciphertext provided with the key and the nonce
+// provided using AES-GCM, authenticating the set identifier provided with it.
+// It returns an error if the ciphertext cannot be authenticated.
+func decryptBlob(key, setID, nonce, ciphertext []byte) ([]byte, error) {
+	aead, err := blobCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := aead.Open(nil, nonce, ciphertext, setID)
+	if err != nil {
+		return nil, ErrBlobAuthentication
+	}
+	return data, nil
+}
+
+// blobCipher returns the AES-GCM cipher for the key provided.
+func blobCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidEnvelope, err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,99 @@
+package gosss
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestHideRecoverBlob(t *testing.T) {
+	data := make([]byte, 1<<20)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	config := &Config{
+		Shares: 5,
+		Min:    3,
+	}
+	envelope, shares, err := HideBlob(data, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the shares only hide the key, so they are short
+	for _, share := range shares {
+		if len(share) > 256 {
+			t.Errorf("unexpected share length: %d", len(share))
+		}
+	}
+	// the envelope can be encoded and decoded
+	b, err := envelope.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decoded := &Envelope{}
+	if err := decoded.UnmarshalBinary(b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	recovered, err := RecoverBlob(decoded, shares[2:], config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(recovered, data) {
+		t.Errorf("unexpected data recovered")
+	}
+	// modified ciphertext
+	decoded.Ciphertext[100] ^= 0x01
+	if _, err := RecoverBlob(decoded, shares[2:], config); err != ErrBlobAuthentication {
+		t.Errorf("expected %v, got %v", ErrBlobAuthentication, err)
+	}
+	decoded.Ciphertext[100] ^= 0x01
+	// wrong key recovered from a modified share
+	wrong := append([]string{}, shares[:3]...)
+	parsed := Share{}
+	if err := parsed.UnmarshalText([]byte(wrong[0])); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parsed.Values[0].Add(parsed.Values[0], big.NewInt(1))
+	wrong[0] = parsed.String()
+	if _, err := RecoverBlob(decoded, wrong, config); err != ErrBlobAuthentication {
+		t.Errorf("expected %v, got %v", ErrBlobAuthentication, err)
+	}
+	// shares of a different set
+	_, otherShares, err := HideBlob(data[:10], config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := RecoverBlob(decoded, otherShares, config); err != ErrShareSetMismatch {
+		t.Errorf("expected %v, got %v", ErrShareSetMismatch, err)
+	}
+	// invalid envelopes
+	if err := decoded.UnmarshalBinary(b[:10]); err != ErrInvalidEnvelope {
+		t.Errorf("expected %v, got %v", ErrInvalidEnvelope, err)
+	}
+	if _, err := RecoverBlob(nil, shares, config); err != ErrInvalidEnvelope {
+		t.Errorf("expected %v, got %v", ErrInvalidEnvelope, err)
+	}
+}
+
+func TestHideRecoverBlobMnemonic(t *testing.T) {
+	data := []byte("blob with mnemonic key shares")
+	config := &Config{Shares: 5, Min: 3, Encoding: MnemonicEncoding}
+	envelope, shares, err := HideBlob(data, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the key shares are encoded like HideMessage does for the same config
+	for _, share := range shares {
+		if !isMnemonic(share) {
+			t.Fatalf("expected mnemonic, got %s", share)
+		}
+	}
+	recovered, err := RecoverBlob(envelope, shares[1:4], config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(recovered, data) {
+		t.Errorf("expected %s, got %s", data, recovered)
+	}
+}
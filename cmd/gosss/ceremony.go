@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/lucasmenendez/gosss"
+	"golang.org/x/term"
+)
+
+// runCeremony runs the ceremony subcommand, an interactive recovery where the
+// holders type their shares one at a time. The shares are read from the
+// terminal without echoing them, or line by line if the standard input is
+// not a terminal. Every share is validated as it arrives: it must be decoded,
+// have an index not provided before and belong to the set of the first one.
+// After every accepted share, it shows how many more are required, and when
+// the threshold is met, it recovers the message with RecoverMessage and
+// writes it to the output file or to the standard output. The prompts and
+// the transcript, with the indexes of the holders that took part, are written
+// to the standard error, and also to the transcript file if it is provided.
+// Legacy shares do not include the threshold, so it must be provided with a
+// flag to use them.
+func runCeremony(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("ceremony", "[flags]", stderr)
+	prime := fs.String("prime", "", "prime number in decimal or name of a registered prime (default from the shares)")
+	threshold := fs.Int("threshold", 0, "minimum number of shares, only required for legacy shares")
+	output := fs.String("out", "", "file to write the message to (default stdout)")
+	transcriptPath := fs.String("transcript", "", "file to append the transcript of the ceremony to")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return errUsage
+	}
+	conf := &gosss.Config{}
+	if err := setPrime(conf, *prime); err != nil {
+		return err
+	}
+	transcript := &bytes.Buffer{}
+	logf := func(format string, a ...any) {
+		fmt.Fprintf(stderr, format, a...)
+		fmt.Fprintf(transcript, format, a...)
+	}
+	readShare := shareReader(stdin, stderr)
+	inputs, shares := []string{}, []gosss.Share{}
+	required := *threshold
+	logf("recovery ceremony started\n")
+	for required == 0 || len(shares) < required {
+		fmt.Fprintf(stderr, "share of holder %d: ", len(shares)+1)
+		input, err := readShare()
+		if err != nil {
+			logf("ceremony aborted with %d shares\n", len(shares))
+			if err == io.EOF {
+				return gosss.ErrNotEnoughShares
+			}
+			return err
+		}
+		if input == "" {
+			continue
+		}
+		share, err := checkCeremonyShare(input, shares)
+		if err != nil {
+			fmt.Fprintf(stderr, "share rejected: %v\n", err)
+			continue
+		}
+		if required == 0 {
+			if share.Threshold == 0 {
+				fmt.Fprintln(stderr, "share rejected: legacy shares require the -threshold flag")
+				continue
+			}
+			required = share.Threshold
+			logf("set %s, %d shares required\n", hex.EncodeToString(share.SetID), required)
+		}
+		inputs, shares = append(inputs, input), append(shares, share)
+		logf("accepted share of holder with index %s, %d more required\n", share.Index, max(required-len(shares), 0))
+	}
+	message, err := gosss.RecoverMessage(inputs, conf)
+	if err != nil {
+		logf("recovery failed: %v\n", err)
+	} else {
+		indexes := make([]string, len(shares))
+		for i, share := range shares {
+			indexes[i] = share.Index.String()
+		}
+		logf("message recovered with the shares of the holders with indexes %s\n", strings.Join(indexes, ", "))
+	}
+	if *transcriptPath != "" {
+		if err := appendFile(*transcriptPath, transcript.Bytes()); err != nil {
+			return err
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err := stdout.Write(message)
+		return err
+	}
+	return os.WriteFile(*output, message, 0o600)
+}
+
+// checkCeremonyShare decodes the share provided and checks that it can be
+// used with the shares accepted before: its index must be different and it
+// must belong to the same set and epoch.
+func checkCeremonyShare(input string, accepted []gosss.Share) (gosss.Share, error) {
+	var share gosss.Share
+	if err := share.UnmarshalText([]byte(input)); err != nil {
+		return share, err
+	}
+	for _, prev := range accepted {
+		if prev.Index.Cmp(share.Index) == 0 {
+			return share, gosss.ErrDuplicatedShare
+		}
+	}
+	if len(accepted) > 0 {
+		if err := matchShares(accepted[0], share); err != nil {
+			return share, err
+		}
+	}
+	return share, nil
+}
+
+// shareReader returns a function that reads the next share from the reader
+// provided. If the reader is a terminal, the shares are read without echoing
+// them, otherwise they are read line by line. The function returns io.EOF
+// when there are no more shares.
+func shareReader(stdin io.Reader, stderr io.Writer) func() (string, error) {
+	if f, ok := stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		return func() (string, error) {
+			b, err := term.ReadPassword(int(f.Fd()))
+			fmt.Fprintln(stderr)
+			if err != nil {
+				return "", err
+			}
+			return strings.TrimSpace(string(b)), nil
+		}
+	}
+	scanner := bufio.NewScanner(stdin)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	return func() (string, error) {
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", io.EOF
+		}
+		fmt.Fprintln(stderr)
+		return strings.TrimSpace(scanner.Text()), nil
+	}
+}
+
+// appendFile appends the content provided to the file of the path provided,
+// creating it if it does not exist.
+func appendFile(path string, content []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/lucasmenendez/gosss"
+)
+
+// runCombine runs the combine subcommand. It reads the shares from the
+// arguments, which can be files with one share per line or shares, or from
+// the standard input, one per line, recovers the message and writes it to the
+// output file or to the standard output.
+func runCombine(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("combine", "[flags] [file or share ...]", stderr)
+	prime := fs.String("prime", "", "prime number in decimal or name of a registered prime (default from the shares)")
+	output := fs.String("out", "", "file to write the message to (default stdout)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	shares, err := readShares(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	conf := &gosss.Config{}
+	if err := setPrime(conf, *prime); err != nil {
+		return err
+	}
+	message, err := gosss.RecoverMessage(shares, conf)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err := stdout.Write(message)
+		return err
+	}
+	return os.WriteFile(*output, message, 0o600)
+}
+
+// readShares returns the shares of the arguments provided. Every argument is
+// the path of a file with one share per line, or a share if it is not a file.
+// If no argument is provided, the shares are read from the reader provided,
+// one per line. Empty lines are ignored.
+func readShares(args []string, stdin io.Reader) ([]string, error) {
+	if len(args) == 0 {
+		return readLines(stdin)
+	}
+	shares := []string{}
+	for _, arg := range args {
+		if info, err := os.Stat(arg); err == nil && info.Mode().IsRegular() {
+			content, err := os.ReadFile(arg)
+			if err != nil {
+				return nil, err
+			}
+			lines, err := readLines(bytes.NewReader(content))
+			if err != nil {
+				return nil, err
+			}
+			shares = append(shares, lines...)
+			continue
+		}
+		shares = append(shares, strings.TrimSpace(arg))
+	}
+	return shares, nil
+}
+
+// readLines returns the non empty lines of the reader provided, without
+// surrounding spaces.
+func readLines(r io.Reader) ([]string, error) {
+	lines := []string{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/lucasmenendez/gosss"
+)
+
+// runInspect runs the inspect subcommand. It decodes every share read from
+// the arguments or the standard input, like the combine subcommand, and
+// prints its index, the number and size of its values and its metadata, or
+// the error if it cannot be decoded. It returns the error of the first share
+// that cannot be decoded.
+func runInspect(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("inspect", "[file or share ...]", stderr)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	inputs, err := readShares(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	var firstErr error
+	for i, input := range inputs {
+		var share gosss.Share
+		if err := share.UnmarshalText([]byte(input)); err != nil {
+			fmt.Fprintf(stdout, "share %d: error: %v\n", i, err)
+			if firstErr == nil {
+				firstErr = &gosss.ShareError{Position: i, Err: err}
+			}
+			continue
+		}
+		printShare(stdout, i, share)
+	}
+	return firstErr
+}
+
+// printShare prints the index, the values and the metadata of the share
+// provided, identified by its position.
+func printShare(w io.Writer, position int, share gosss.Share) {
+	size := 0
+	for _, value := range share.Values {
+		size = max(size, len(value.Bytes()))
+	}
+	fmt.Fprintf(w, "share %d:\n", position)
+	fmt.Fprintf(w, "  index:     %s\n", share.Index)
+	fmt.Fprintf(w, "  chunks:    %d\n", len(share.Values))
+	fmt.Fprintf(w, "  size:      %d bytes per value\n", size)
+	if share.SetID == nil {
+		fmt.Fprintf(w, "  format:    legacy, no metadata\n")
+		return
+	}
+	fmt.Fprintf(w, "  format:    versioned\n")
+	fmt.Fprintf(w, "  set:       %s\n", hex.EncodeToString(share.SetID))
+	fmt.Fprintf(w, "  prime id:  %s\n", hex.EncodeToString(share.PrimeID))
+	if share.PrimeName != "" {
+		fmt.Fprintf(w, "  prime:     %s\n", share.PrimeName)
+	}
+	fmt.Fprintf(w, "  threshold: %d of %d\n", share.Threshold, share.Total)
+	fmt.Fprintf(w, "  epoch:     %d\n", share.Epoch)
+	if share.Blinding {
+		fmt.Fprintf(w, "  blinding:  verifies the share, can not recover the message\n")
+	}
+}
@@ -0,0 +1,211 @@
+// Command gosss hides and recovers messages using the Shamir Secret Sharing
+// algorithm from the command line. Run it without arguments to see the list
+// of commands.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"slices"
+
+	"github.com/lucasmenendez/gosss"
+)
+
+// Exit codes of the command, every error of the package is mapped to one of
+// them by exitCode.
+const (
+	exitOK            = 0
+	exitError         = 1
+	exitUsage         = 2
+	exitConfig        = 3
+	exitInvalidShare  = 4
+	exitNotEnough     = 5
+	exitShareMismatch = 6
+	exitRecover       = 7
+	exitInconsistent  = 8
+)
+
+// exitCodes maps the errors of the package to the exit code of the command.
+var exitCodes = map[int][]error{
+	exitConfig: {
+		gosss.ErrRequiredConfig, gosss.ErrConfigShares, gosss.ErrConfigMin,
+		gosss.ErrConfigNoPrime, gosss.ErrConfigInvalidPrime, gosss.ErrMessageTooLong,
+		gosss.ErrConfigBackend, gosss.ErrConfigPrimeName, gosss.ErrConfigPrimeTooSmall, gosss.ErrConfigEncoding,
+	},
+	exitInvalidShare: {
+		gosss.ErrInvalidShare, gosss.ErrShareTooLong, gosss.ErrUnsupportedVersion,
+		gosss.ErrShareChecksum, gosss.ErrTruncatedStream, gosss.ErrInvalidMnemonic,
+		gosss.ErrMnemonicChecksum,
+	},
+	exitNotEnough: {
+		gosss.ErrNotEnoughShares,
+	},
+	exitShareMismatch: {
+		gosss.ErrDuplicatedShare, gosss.ErrShareSetMismatch, gosss.ErrPrimeMismatch,
+		gosss.ErrEpochMismatch, gosss.ErrLegacyShare,
+	},
+	exitRecover: {
+		gosss.ErrDecodingMessage, gosss.ErrTooManyFaultyShares, gosss.ErrBlobAuthentication,
+	},
+}
+
+var (
+	// errUsage is returned by the commands when their arguments are not
+	// valid.
+	errUsage = errors.New("invalid arguments")
+	// errInconsistent is returned by the verify subcommand when a share does
+	// not lie on the polynomials of the rest of them.
+	errInconsistent = errors.New("share is not consistent with the rest")
+)
+
+// command struct defines a subcommand of the tool, with its description and
+// the function that runs it with its arguments.
+type command struct {
+	description string
+	run         func(args []string, stdin io.Reader, stdout, stderr io.Writer) error
+}
+
+// commands returns the subcommands of the tool by name.
+func commands() map[string]command {
+	return map[string]command{
+		"split": {
+			description: "hide a message and write its shares",
+			run:         runSplit,
+		},
+		"combine": {
+			description: "recover a message from its shares",
+			run:         runCombine,
+		},
+		"ceremony": {
+			description: "recover a message typing the shares one at a time",
+			run:         runCeremony,
+		},
+		"inspect": {
+			description: "decode shares and print their metadata",
+			run:         runInspect,
+		},
+		"serve": {
+			description: "collect the shares from the holders over HTTP",
+			run:         runServe,
+		},
+		"verify": {
+			description: "check that shares can be used together",
+			run:         runVerify,
+		},
+	}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the subcommand of the arguments provided and returns the exit code
+// of the command.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cmds := commands()
+	if len(args) == 0 {
+		usage(stderr, cmds)
+		return exitUsage
+	}
+	cmd, ok := cmds[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command: %s\n", args[0])
+		usage(stderr, cmds)
+		return exitUsage
+	}
+	if err := cmd.run(args[1:], stdin, stdout, stderr); err != nil {
+		if !errors.Is(err, errUsage) {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+		}
+		return exitCode(err)
+	}
+	return exitOK
+}
+
+// usage writes the list of subcommands to the writer provided.
+func usage(w io.Writer, cmds map[string]command) {
+	fmt.Fprintln(w, "usage: gosss <command> [flags]")
+	fmt.Fprintln(w, "\ncommands:")
+	for _, name := range sortedNames(cmds) {
+		fmt.Fprintf(w, "  %-10s %s\n", name, cmds[name].description)
+	}
+	fmt.Fprintln(w, "\nexit codes:")
+	fmt.Fprintln(w, "  0 success, 1 error, 2 invalid arguments, 3 invalid configuration,")
+	fmt.Fprintln(w, "  4 invalid share, 5 not enough shares, 6 shares do not match,")
+	fmt.Fprintln(w, "  7 the message cannot be recovered, 8 inconsistent shares")
+}
+
+// exitCode returns the exit code of the error provided, looking for the
+// errors of the package wrapped in it.
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	if errors.Is(err, errUsage) {
+		return exitUsage
+	}
+	if errors.Is(err, errInconsistent) {
+		return exitInconsistent
+	}
+	for code, errs := range exitCodes {
+		for _, target := range errs {
+			if errors.Is(err, target) {
+				return code
+			}
+		}
+	}
+	return exitError
+}
+
+// setPrime sets the prime number of the configuration from the string
+// provided, which can be the name of a registered prime or a prime number in
+// decimal. An empty string keeps the default prime.
+func setPrime(conf *gosss.Config, prime string) error {
+	if prime == "" {
+		return nil
+	}
+	if _, err := gosss.LookupPrime(prime); err == nil {
+		conf.PrimeName = prime
+		return nil
+	}
+	var ok bool
+	if conf.Prime, ok = new(big.Int).SetString(prime, 10); !ok {
+		return gosss.ErrConfigInvalidPrime
+	}
+	return nil
+}
+
+// sortedNames returns the names of the subcommands provided, sorted.
+func sortedNames(cmds map[string]command) []string {
+	names := make([]string, 0, len(cmds))
+	for name := range cmds {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// newFlagSet returns a flag set for the subcommand provided that writes its
+// errors and usage to the writer provided.
+func newFlagSet(name, usage string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: gosss %s %s\n\nflags:\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses the arguments provided with the flag set provided. It
+// returns errUsage if they cannot be parsed or if the help is requested.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	return nil
+}
@@ -0,0 +1,355 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucasmenendez/gosss"
+)
+
+func TestSplitCombine(t *testing.T) {
+	message := "secret message from the command line"
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	args := []string{"split", "-shares", "5", "-threshold", "3", "-prime", gosss.PrimeSecp256k1}
+	if code := run(args, strings.NewReader(message), stdout, stderr); code != exitOK {
+		t.Fatalf("unexpected exit code: %d: %s", code, stderr)
+	}
+	shares := strings.Fields(stdout.String())
+	if len(shares) != 5 {
+		t.Fatalf("unexpected number of shares: %d", len(shares))
+	}
+	// from the standard input
+	stdout.Reset()
+	input := strings.NewReader(strings.Join(shares[2:], "\n"))
+	if code := run([]string{"combine"}, input, stdout, stderr); code != exitOK {
+		t.Fatalf("unexpected exit code: %d: %s", code, stderr)
+	}
+	if stdout.String() != message {
+		t.Errorf("expected %s, got %s", message, stdout)
+	}
+	// from files and arguments
+	dir := t.TempDir()
+	path := filepath.Join(dir, "share.txt")
+	if err := os.WriteFile(path, []byte(shares[0]+"\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stdout.Reset()
+	if code := run([]string{"combine", path, shares[1], shares[4]}, nil, stdout, stderr); code != exitOK {
+		t.Fatalf("unexpected exit code: %d: %s", code, stderr)
+	}
+	if stdout.String() != message {
+		t.Errorf("expected %s, got %s", message, stdout)
+	}
+}
+
+func TestSplitOutDir(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "message.txt")
+	if err := os.WriteFile(input, []byte("file message"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	outDir := filepath.Join(dir, "shares")
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	args := []string{"split", "-shares", "4", "-threshold", "2", "-out-dir", outDir, input}
+	if code := run(args, nil, stdout, stderr); code != exitOK {
+		t.Fatalf("unexpected exit code: %d: %s", code, stderr)
+	}
+	for i := 1; i <= 4; i++ {
+		if _, err := os.Stat(filepath.Join(outDir, fmt.Sprintf("share-%d.txt", i))); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	output := filepath.Join(dir, "recovered.txt")
+	args = []string{"combine", "-out", output, filepath.Join(outDir, "share-1.txt"), filepath.Join(outDir, "share-3.txt")}
+	if code := run(args, nil, stdout, stderr); code != exitOK {
+		t.Fatalf("unexpected exit code: %d: %s", code, stderr)
+	}
+	if recovered, _ := os.ReadFile(output); string(recovered) != "file message" {
+		t.Errorf("unexpected message: %s", recovered)
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	shares := &bytes.Buffer{}
+	run([]string{"split", "-shares", "5", "-threshold", "4"}, strings.NewReader("msg"), shares, stdout)
+	lines := strings.Fields(shares.String())
+	tests := []struct {
+		args     []string
+		stdin    string
+		expected int
+	}{
+		{nil, "", exitUsage},
+		{[]string{"unknown"}, "", exitUsage},
+		{[]string{"split", "-unknown"}, "", exitUsage},
+		{[]string{"split", "-shares", "2", "-threshold", "1"}, "msg", exitConfig},
+		{[]string{"split", "-shares", "5", "-threshold", "3", "-prime", "10008"}, "msg", exitConfig},
+		{[]string{"combine", "invalid"}, "", exitInvalidShare},
+		{[]string{"combine", lines[0], lines[1]}, "", exitNotEnough},
+		{[]string{"combine", lines[0], lines[0], lines[1], lines[2]}, "", exitShareMismatch},
+	}
+	for _, test := range tests {
+		if code := run(test.args, strings.NewReader(test.stdin), stdout, stdout); code != test.expected {
+			t.Errorf("expected %d, got %d for %v", test.expected, code, test.args)
+		}
+	}
+}
+
+func TestInspectVerify(t *testing.T) {
+	shares, err := gosss.HideMessageShares([]byte("inspect me"), &gosss.Config{Shares: 5, Min: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	inputs := make([]string, len(shares))
+	for i, share := range shares {
+		inputs[i] = share.String()
+	}
+	stdout := &bytes.Buffer{}
+	if code := run([]string{"inspect", inputs[0]}, nil, stdout, stdout); code != exitOK {
+		t.Fatalf("unexpected exit code: %d: %s", code, stdout)
+	}
+	for _, expected := range []string{"index:     1", "threshold: 3 of 5", "epoch:     0"} {
+		if !strings.Contains(stdout.String(), expected) {
+			t.Errorf("expected %q in %s", expected, stdout)
+		}
+	}
+	if code := run([]string{"inspect", inputs[0], "zz"}, nil, stdout, stdout); code != exitInvalidShare {
+		t.Errorf("expected %d, got %d", exitInvalidShare, code)
+	}
+	if code := run(append([]string{"verify"}, inputs...), nil, stdout, stdout); code != exitOK {
+		t.Errorf("expected %d, got %d: %s", exitOK, code, stdout)
+	}
+	// a wrong share is reported as inconsistent
+	shares[4].Values[0].Add(shares[4].Values[0], big.NewInt(1))
+	wrong := append(append([]string{"verify"}, inputs[:4]...), shares[4].String())
+	stdout.Reset()
+	if code := run(wrong, nil, stdout, stdout); code != exitInconsistent {
+		t.Errorf("expected %d, got %d: %s", exitInconsistent, code, stdout)
+	}
+	if !strings.Contains(stdout.String(), "share 4:") {
+		t.Errorf("expected share 4 in %s", stdout)
+	}
+	// a wrong share among the first k shares is identified, not the rest
+	shares[0].Values[0].Add(shares[0].Values[0], big.NewInt(1))
+	wrong = append([]string{"verify", shares[0].String()}, inputs[1:]...)
+	stdout.Reset()
+	if code := run(wrong, nil, stdout, stdout); code != exitInconsistent {
+		t.Errorf("expected %d, got %d: %s", exitInconsistent, code, stdout)
+	}
+	if !strings.Contains(stdout.String(), "share 0:") || strings.Contains(stdout.String(), "share 3:") ||
+		strings.Contains(stdout.String(), "share 4:") {
+		t.Errorf("expected only share 0 in %s", stdout)
+	}
+	// with a single extra share the wrong share cannot be identified
+	stdout.Reset()
+	if code := run(wrong[:5], nil, stdout, stdout); code != exitInconsistent {
+		t.Errorf("expected %d, got %d: %s", exitInconsistent, code, stdout)
+	}
+	if strings.Contains(stdout.String(), "share 1:") || strings.Contains(stdout.String(), "share 3:") {
+		t.Errorf("expected no share blamed in %s", stdout)
+	}
+	// shares of another set
+	other, _ := gosss.HideMessageShares([]byte("other"), &gosss.Config{Shares: 5, Min: 3})
+	mixed := append(append([]string{"verify"}, inputs[:3]...), other[3].String())
+	if code := run(mixed, nil, stdout, stdout); code != exitShareMismatch {
+		t.Errorf("expected %d, got %d", exitShareMismatch, code)
+	}
+	if code := run([]string{"verify", inputs[0], inputs[1]}, nil, stdout, stdout); code != exitNotEnough {
+		t.Errorf("expected %d, got %d", exitNotEnough, code)
+	}
+}
+
+func TestCeremony(t *testing.T) {
+	message := "ceremony message"
+	shares, err := gosss.HideMessage([]byte(message), &gosss.Config{Shares: 5, Min: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// invalid, duplicated and empty inputs are rejected without aborting
+	input := strings.Join([]string{"invalid", shares[1], shares[1], "", shares[3], shares[0], shares[4]}, "\n")
+	transcript := filepath.Join(t.TempDir(), "transcript.txt")
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	args := []string{"ceremony", "-transcript", transcript}
+	if code := run(args, strings.NewReader(input), stdout, stderr); code != exitOK {
+		t.Fatalf("unexpected exit code: %d: %s", code, stderr)
+	}
+	if stdout.String() != message {
+		t.Errorf("expected %s, got %s", message, stdout)
+	}
+	for _, expected := range []string{"share rejected", "2 more required", "1 more required", "indexes 2, 4, 1"} {
+		if !strings.Contains(stderr.String(), expected) {
+			t.Errorf("expected %q in %s", expected, stderr)
+		}
+	}
+	content, err := os.ReadFile(transcript)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(content), "indexes 2, 4, 1") || strings.Contains(string(content), shares[1]) {
+		t.Errorf("unexpected transcript: %s", content)
+	}
+	// the ceremony is aborted if the input ends before the threshold
+	input = strings.Join(shares[:2], "\n")
+	if code := run([]string{"ceremony"}, strings.NewReader(input), stdout, stderr); code != exitNotEnough {
+		t.Errorf("expected %d, got %d", exitNotEnough, code)
+	}
+}
+
+// serveShares runs the serve subcommand with the arguments provided and
+// submits the shares provided to the address written to the stderr with the
+// client provided. It returns the exit code and the standard output.
+func serveShares(t *testing.T, args []string, client *http.Client, shares []string) (int, string) {
+	t.Helper()
+	stderrReader, stderr := io.Pipe()
+	lines := make(chan string, 16)
+	go func() {
+		scanner := bufio.NewScanner(stderrReader)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+	stdout := &bytes.Buffer{}
+	done := make(chan int)
+	go func() {
+		done <- run(append([]string{"serve"}, args...), nil, stdout, stderr)
+		stderr.Close()
+	}()
+	// the holders submit their shares to the address written to the stderr
+	var url string
+	for line := range lines {
+		if _, after, ok := strings.Cut(line, "submit the shares to "); ok {
+			url, _, _ = strings.Cut(after, " ")
+			break
+		}
+	}
+	if url == "" {
+		t.Fatalf("expected the address of the session")
+	}
+	for _, share := range shares {
+		res, err := client.Post(url, "application/json", strings.NewReader(`{"share": "`+share+`"}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		res.Body.Close()
+	}
+	go func() {
+		for range lines {
+		}
+	}()
+	return <-done, stdout.String()
+}
+
+func TestServe(t *testing.T) {
+	message := "served message"
+	shares, err := gosss.HideMessage([]byte(message), &gosss.Config{Shares: 4, Min: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	code, stdout := serveShares(t, []string{"-addr", "127.0.0.1:0"}, http.DefaultClient,
+		[]string{"invalid", shares[3], shares[1]})
+	if code != exitOK {
+		t.Fatalf("unexpected exit code: %d", code)
+	}
+	if stdout != message {
+		t.Errorf("expected %s, got %s", message, stdout)
+	}
+	// the session is wiped after the timeout
+	args := []string{"serve", "-addr", "127.0.0.1:0", "-timeout", "100ms"}
+	if code := run(args, nil, io.Discard, io.Discard); code != exitNotEnough {
+		t.Errorf("expected %d, got %d", exitNotEnough, code)
+	}
+	// plain HTTP is refused on non-loopback addresses
+	args = []string{"serve", "-addr", "0.0.0.0:0"}
+	if code := run(args, nil, io.Discard, io.Discard); code != exitUsage {
+		t.Errorf("expected %d, got %d", exitUsage, code)
+	}
+	args = []string{"serve", "-addr", "127.0.0.1:0", "-tls-cert", "cert.pem"}
+	if code := run(args, nil, io.Discard, io.Discard); code != exitUsage {
+		t.Errorf("expected %d, got %d", exitUsage, code)
+	}
+}
+
+func TestServeTLS(t *testing.T) {
+	// generate a self-signed certificate for the loopback address
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dir := t.TempDir()
+	certPath, keyPath := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := os.WriteFile(certPath, certPEM, 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(certPEM)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+
+	message := "served over tls"
+	shares, err := gosss.HideMessage([]byte(message), &gosss.Config{Shares: 4, Min: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	args := []string{"-addr", "127.0.0.1:0", "-tls-cert", certPath, "-tls-key", keyPath}
+	code, stdout := serveShares(t, args, client, shares[:2])
+	if code != exitOK {
+		t.Fatalf("unexpected exit code: %d", code)
+	}
+	if stdout != message {
+		t.Errorf("expected %s, got %s", message, stdout)
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	message := "message in words"
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	args := []string{"split", "-shares", "3", "-threshold", "2", "-words"}
+	if code := run(args, strings.NewReader(message), stdout, stderr); code != exitOK {
+		t.Fatalf("unexpected exit code: %d: %s", code, stderr)
+	}
+	shares := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(shares) != 3 || len(strings.Fields(shares[0])) < 2 {
+		t.Fatalf("unexpected shares: %s", stdout)
+	}
+	stdout.Reset()
+	if code := run([]string{"combine", shares[2], shares[0]}, nil, stdout, stderr); code != exitOK {
+		t.Fatalf("unexpected exit code: %d: %s", code, stderr)
+	}
+	if stdout.String() != message {
+		t.Errorf("expected %s, got %s", message, stdout)
+	}
+}
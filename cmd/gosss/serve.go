@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lucasmenendez/gosss"
+	"github.com/lucasmenendez/gosss/server"
+)
+
+// servePollInterval is the interval to check the status of the recovery
+// session served by the serve subcommand.
+const servePollInterval = 200 * time.Millisecond
+
+// runServe runs the serve subcommand, which starts a local HTTP recovery
+// server and opens a recovery session on it. The code of the session and the
+// address to submit the shares are written to the standard error, so the
+// owner can send them to the holders, with the token of the owner, to drop
+// wrong shares through the JSON API. The shares are validated as they arrive
+// and, when the threshold is met, the message is recovered correcting the
+// wrong shares and written to the output file or to the standard output, and
+// the server is stopped. If the message cannot be recovered, it keeps
+// collecting shares and tries again with every change of the shares. The
+// session is wiped if it is not completed before the timeout. The server does
+// not serve the route to open other sessions. The shares are served over TLS
+// with the certificate and key provided, and plain HTTP is only allowed on
+// loopback addresses, so the shares do not travel in cleartext.
+func runServe(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("serve", "[flags]", stderr)
+	addr := fs.String("addr", "127.0.0.1:8080", "address to listen on")
+	timeout := fs.Duration("timeout", 15*time.Minute, "time after which the session is wiped")
+	prime := fs.String("prime", "", "prime number in decimal or name of a registered prime (default from the shares)")
+	output := fs.String("out", "", "file to write the message to (default stdout)")
+	tlsCert := fs.String("tls-cert", "", "certificate file to serve over TLS, required for non-loopback addresses")
+	tlsKey := fs.String("tls-key", "", "private key file of the TLS certificate")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 || *timeout <= 0 || (*tlsCert == "") != (*tlsKey == "") {
+		fs.Usage()
+		return errUsage
+	}
+	if *tlsCert == "" && !isLoopback(*addr) {
+		fmt.Fprintln(stderr, "the shares would travel in cleartext, provide -tls-cert and -tls-key to listen on a non-loopback address")
+		return errUsage
+	}
+	conf := &gosss.Config{}
+	if err := setPrime(conf, *prime); err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	scheme := "http"
+	if *tlsCert != "" {
+		cert, err := tls.LoadX509KeyPair(*tlsCert, *tlsKey)
+		if err != nil {
+			listener.Close()
+			return err
+		}
+		listener = tls.NewListener(listener, &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12})
+		scheme = "https"
+	}
+	srv := server.NewClosed(conf, *timeout)
+	httpServer := &http.Server{Handler: srv, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = httpServer.Serve(listener) }()
+	defer httpServer.Shutdown(context.Background())
+
+	session, token, err := srv.Open()
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s://%s/sessions/%s", scheme, listener.Addr(), session.Code)
+	fmt.Fprintf(stderr, "recovery session %s open until %s\n", session.Code, session.ExpiresAt.Format(time.RFC3339))
+	fmt.Fprintf(stderr, "submit the shares to %s/shares as {\"share\": \"...\"}\n", url)
+	fmt.Fprintf(stderr, "owner token to drop shares: %s\n", token)
+	var message []byte
+	indexes := ""
+	for recovered := false; !recovered; {
+		time.Sleep(servePollInterval)
+		status, err := srv.Status(session.Code)
+		if err != nil {
+			if errors.Is(err, server.ErrSessionNotFound) {
+				fmt.Fprintf(stderr, "session expired with %d shares\n", session.Accepted)
+				return gosss.ErrNotEnoughShares
+			}
+			return err
+		}
+		session = status
+		// nothing to do until the shares change
+		if current := strings.Join(session.Indexes, ","); current != indexes {
+			indexes = current
+		} else {
+			continue
+		}
+		fmt.Fprintf(stderr, "shares accepted with indexes [%s], %d required\n", indexes, session.Required)
+		if !session.Complete {
+			continue
+		}
+		if message, err = srv.Reveal(session.Code, token); err != nil {
+			fmt.Fprintf(stderr, "the message cannot be recovered: %v, waiting for more shares\n", err)
+			continue
+		}
+		recovered = true
+	}
+	fmt.Fprintln(stderr, "message recovered")
+	if *output == "" {
+		_, err := stdout.Write(message)
+		return err
+	}
+	return os.WriteFile(*output, message, 0o600)
+}
+
+// isLoopback returns true if the host of the address provided is a loopback
+// address or localhost.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
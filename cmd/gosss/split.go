@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/lucasmenendez/gosss"
+)
+
+// runSplit runs the split subcommand. It reads the message from the file
+// provided, as flag or argument, or from the standard input, hides it with
+// the number of shares, threshold and prime provided, and writes every share
+// to its own file in the output directory, or to the standard output, one per
+// line. The shares are encoded as hexadecimal strings, or as sequences of
+// words if the words flag is provided.
+func runSplit(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("split", "[flags] [file]", stderr)
+	shares := fs.Int("shares", 0, "number of shares to generate")
+	threshold := fs.Int("threshold", 0, "minimum number of shares to recover the message")
+	prime := fs.String("prime", "", "prime number in decimal or name of a registered prime")
+	input := fs.String("in", "", "file to read the message from (default stdin)")
+	outDir := fs.String("out-dir", "", "directory to write a file per share (default stdout)")
+	words := fs.Bool("words", false, "encode the shares as sequences of words instead of hexadecimal")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *input == "" && fs.NArg() == 1 {
+		*input = fs.Arg(0)
+	} else if fs.NArg() > 0 {
+		fs.Usage()
+		return errUsage
+	}
+	message, err := readInput(*input, stdin)
+	if err != nil {
+		return err
+	}
+	conf := &gosss.Config{Shares: *shares, Min: *threshold}
+	if *words {
+		conf.Encoding = gosss.MnemonicEncoding
+	}
+	if err := setPrime(conf, *prime); err != nil {
+		return err
+	}
+	result, err := gosss.HideMessage(message, conf)
+	if err != nil {
+		return err
+	}
+	if *outDir == "" {
+		for _, share := range result {
+			fmt.Fprintln(stdout, share)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(*outDir, 0o700); err != nil {
+		return err
+	}
+	for i, share := range result {
+		path := filepath.Join(*outDir, fmt.Sprintf("share-%d.txt", i+1))
+		if err := os.WriteFile(path, []byte(share+"\n"), 0o600); err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, path)
+	}
+	return nil
+}
+
+// readInput reads the content of the file provided, or of the reader provided
+// if the path is empty or "-".
+func readInput(path string, stdin io.Reader) ([]byte, error) {
+	if path == "" || path == "-" {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/lucasmenendez/gosss"
+)
+
+// runVerify runs the verify subcommand. It decodes every share read from the
+// arguments or the standard input, like the combine subcommand, and checks
+// that they can be used together: every share must be decoded, have a
+// different index and belong to the same set and epoch of the first one. Then
+// it identifies the valid shares that do not lie on the polynomials of the
+// rest with RecoverMessageSharesRobust, which requires more shares than the
+// threshold and can identify up to (n - k) / 2 wrong shares, where n is the
+// number of valid shares and k the threshold. Unlike VerifyConsistency, it
+// does not trust the first k shares, so a wrong share is never blamed on the
+// others. If there are too many wrong shares to identify them, the shares are
+// reported as inconsistent without blaming any of them. It prints every
+// problem found and returns the error of the first one, so the exit code
+// identifies it.
+func runVerify(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("verify", "[flags] [file or share ...]", stderr)
+	prime := fs.String("prime", "", "prime number in decimal or name of a registered prime (default from the shares)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	inputs, err := readShares(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	conf := &gosss.Config{}
+	if err := setPrime(conf, *prime); err != nil {
+		return err
+	}
+	var firstErr error
+	report := func(position int, err error) {
+		fmt.Fprintf(stdout, "share %d: %v\n", position, err)
+		if firstErr == nil {
+			firstErr = &gosss.ShareError{Position: position, Err: err}
+		}
+	}
+	// decode the shares and discard the duplicated ones and the ones that
+	// do not match the first valid share
+	valid, positions := []gosss.Share{}, []int{}
+	indexes := map[string]int{}
+	for i, input := range inputs {
+		var share gosss.Share
+		if err := share.UnmarshalText([]byte(input)); err != nil {
+			report(i, err)
+			continue
+		}
+		if first, ok := indexes[share.Index.String()]; ok {
+			report(i, fmt.Errorf("%w: same index as share %d", gosss.ErrDuplicatedShare, first))
+			continue
+		}
+		if len(valid) > 0 {
+			if err := matchShares(valid[0], share); err != nil {
+				report(i, fmt.Errorf("%w: share %d", err, positions[0]))
+				continue
+			}
+		}
+		indexes[share.Index.String()] = i
+		valid = append(valid, share)
+		positions = append(positions, i)
+	}
+	if len(valid) == 0 {
+		if firstErr == nil {
+			return gosss.ErrNotEnoughShares
+		}
+		return firstErr
+	}
+	// check the consistency of the valid shares
+	threshold := valid[0].Threshold
+	switch {
+	case threshold == 0:
+		fmt.Fprintln(stdout, "legacy shares, the threshold is unknown, consistency not checked")
+	case len(valid) < threshold:
+		fmt.Fprintf(stdout, "%d valid shares, %d required to recover the message\n", len(valid), threshold)
+		if firstErr == nil {
+			firstErr = gosss.ErrNotEnoughShares
+		}
+	case len(valid) == threshold:
+		fmt.Fprintf(stdout, "%d valid shares, consistency requires more than %d\n", len(valid), threshold)
+	default:
+		_, faulty, err := gosss.RecoverMessageSharesRobust(valid, conf)
+		if errors.Is(err, gosss.ErrTooManyFaultyShares) {
+			err = fmt.Errorf("%w: too many wrong shares to identify them", errInconsistent)
+		}
+		if err != nil {
+			fmt.Fprintf(stdout, "error: %v\n", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			break
+		}
+		for _, i := range faulty {
+			report(positions[i], fmt.Errorf("%w: not on the polynomials of the rest", errInconsistent))
+		}
+		if len(faulty) == 0 {
+			fmt.Fprintf(stdout, "%d valid shares, consistent\n", len(valid))
+		}
+	}
+	return firstErr
+}
+
+// matchShares checks that the share provided belongs to the same set and
+// epoch of the first share provided, and that both are legacy or versioned
+// shares with the same number of chunks.
+func matchShares(first, share gosss.Share) error {
+	if (first.SetID == nil) != (share.SetID == nil) || len(first.Values) != len(share.Values) ||
+		!bytes.Equal(first.SetID, share.SetID) || !bytes.Equal(first.PrimeID, share.PrimeID) ||
+		first.Threshold != share.Threshold || first.Total != share.Total {
+		return gosss.ErrShareSetMismatch
+	}
+	if first.Epoch != share.Epoch {
+		return gosss.ErrEpochMismatch
+	}
+	return nil
+}
@@ -38,11 +38,32 @@ func wasmResult(data interface{}, err error) js.Value {
 	return js.ValueOf(string(result))
 }
 
+// setPrime sets the prime number of the configuration from the string
+// provided, which can be the name of a registered prime or a prime number in
+// decimal.
+func setPrime(conf *gosss.Config, strPrime string) error {
+	if _, err := gosss.LookupPrime(strPrime); err == nil {
+		conf.PrimeName = strPrime
+		return nil
+	}
+	var ok bool
+	if conf.Prime, ok = new(big.Int).SetString(strPrime, 10); !ok {
+		return fmt.Errorf("invalid prime number")
+	}
+	return nil
+}
+
 func main() {
+	// js.ValueOf only supports slices of interface{}
+	primeNames := []interface{}{}
+	for _, name := range gosss.PrimeNames() {
+		primeNames = append(primeNames, name)
+	}
 	gosssClass := js.ValueOf(map[string]interface{}{
 		"defaultPrime": gosss.DefaultPrime.String(),
 		"minShares":    gosss.MinShares,
 		"minMinShares": gosss.MinMinShares,
+		"primeNames":   primeNames,
 	})
 	gosssClass.Set(jsHideMethod, js.FuncOf(func(this js.Value, p []js.Value) interface{} {
 		if len(p) < hidedNArgs {
@@ -54,10 +75,8 @@ func main() {
 			Min:    p[2].Int(),
 		}
 		if len(p) > hidedNArgs {
-			strPrime := p[3].String()
-			var ok bool
-			if conf.Prime, ok = new(big.Int).SetString(strPrime, 10); !ok {
-				return wasmResult(nil, fmt.Errorf("invalid prime number"))
+			if err := setPrime(conf, p[3].String()); err != nil {
+				return wasmResult(nil, err)
 			}
 		}
 		// hide the message
@@ -79,10 +98,8 @@ func main() {
 		}
 		conf := &gosss.Config{}
 		if len(p) > recoverdNArgs {
-			strPrime := p[1].String()
-			var ok bool
-			if conf.Prime, ok = new(big.Int).SetString(strPrime, 10); !ok {
-				return wasmResult(nil, fmt.Errorf("invalid prime number"))
+			if err := setPrime(conf, p[1].String()); err != nil {
+				return wasmResult(nil, err)
 			}
 		}
 		// recover the message
@@ -96,10 +113,8 @@ func main() {
 	gosssClass.Set(jsMaxLenMethod, js.FuncOf(func(this js.Value, p []js.Value) interface{} {
 		conf := &gosss.Config{}
 		if len(p) > 0 {
-			strPrime := p[0].String()
-			var ok bool
-			if conf.Prime, ok = new(big.Int).SetString(strPrime, 10); !ok {
-				return wasmResult(nil, fmt.Errorf("invalid prime number"))
+			if err := setPrime(conf, p[0].String()); err != nil {
+				return wasmResult(nil, err)
 			}
 		}
 		if err := conf.ValidPrime(); err != nil {
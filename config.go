@@ -1,6 +1,7 @@
 package gosss
 
 import (
+	"crypto/sha256"
 	"math/big"
 )
 
@@ -12,19 +13,76 @@ const (
 	MinMinShares = MinShares - 1
 )
 
+// Backend type defines the arithmetic used to hide and recover the messages.
+type Backend int
+
+const (
+	// PrimeBackend hides every chunk of the message as an element of the
+	// finite field defined by the prime number of the configuration. It is the
+	// default backend and supports every feature of the package.
+	PrimeBackend Backend = iota
+	// GF256Backend hides every byte of the message independently over
+	// GF(2^8), so the shares are as long as the message plus one byte for the
+	// x coordinate. It supports up to 255 shares and is only available through
+	// HideMessage and RecoverMessage, because its shares have no metadata.
+	GF256Backend
+)
+
+// Encoding type defines the text representation of the shares returned by
+// HideMessage. RecoverMessage accepts the shares with any of them.
+type Encoding int
+
+const (
+	// HexEncoding encodes every share as the hexadecimal representation of
+	// its binary encoding. It is the default encoding.
+	HexEncoding Encoding = iota
+	// MnemonicEncoding encodes every share as a sequence of words of the
+	// BIP39 english word list with checksum bits, so the holders can write it
+	// on paper or read it aloud. See EncodeMnemonic.
+	MnemonicEncoding
+)
+
 // Config struct defines the configuration for the Shamir Secret Sharing
 // algorithm. It includes the number of shares to generate, the minimum number
 // of shares to recover the secret, and the prime number to use as finite field.
+// The group is only used by the verifiable secret sharing modes to commit to
+// the polynomials, and its order must be the prime number. The backend
+// defines the arithmetic used to hide the message, by default the prime field.
+// The prime number can also be selected by the name it is registered with,
+// which is embedded in the shares so the recovery can select it again. If
+// AutoPrime is set and no prime number is defined, the smallest registered
+// prime that fits the message is selected when it is hidden. MinPrimeBits
+// defines the minimum size in bits of the prime number, as the security level
+// required by the caller. The encoding defines the text representation of the
+// shares returned by HideMessage, by default hexadecimal. If the field is
+// defined, HideMessage and RecoverMessage use it instead of the prime number,
+// for example to hide the message over GF(2^16) or over the scalar field of a
+// curve, and the shares are the encoded elements of the field without
+// metadata. The other operations of the package do not support it.
 type Config struct {
-	Shares int
-	Min    int
-	Prime  *big.Int
+	Shares       int
+	Min          int
+	Prime        *big.Int
+	PrimeName    string
+	AutoPrime    bool
+	MinPrimeBits int
+	Group        Group
+	Backend      Backend
+	Encoding     Encoding
+	Field        Field
 }
 
 // prepare sets the prime number to use as finite field if it is not defined or
-// if it is smaller than 2 bytes. It uses the default prime number defined in
-// the package.
+// if it is smaller than 2 bytes. If the prime number is not defined but its
+// name is, it uses the prime number registered with that name, otherwise it
+// uses the default prime number defined in the package.
 func (c *Config) prepare() {
+	if c.Prime == nil && c.PrimeName != "" {
+		// if the name is not registered, the prime is kept undefined to
+		// return an error when it is validated
+		c.Prime, _ = LookupPrime(c.PrimeName)
+		return
+	}
 	// if the prime number is not defined or is smaller than 2 bytes, it will
 	// use the default prime number
 	if c.Prime == nil || len(c.Prime.Bytes()) < 2 {
@@ -32,11 +90,29 @@ func (c *Config) prepare() {
 	}
 }
 
+// prime returns the prime number of the configuration, or the prime number
+// registered with its name if only the name is defined. It returns nil if
+// neither is defined or if the name is not registered.
+func (c *Config) prime() *big.Int {
+	if c.Prime == nil && c.PrimeName != "" {
+		prime, _ := LookupPrime(c.PrimeName)
+		return prime
+	}
+	return c.Prime
+}
+
 // MaxMessageLen returns the maximum size of the secret that can be hidden
-// in a share, it is the size of the prime number in bytes minus 1, to ensure
-// the secret is smaller than the prime number.
+// in a single polynomial, it is the size of the prime number in bytes minus 1,
+// to ensure the secret is smaller than the prime number. Longer messages are
+// split into chunks of this size, each one hidden in its own polynomial. If
+// only the name of the prime number is defined, the prime registered with it
+// is used. It returns 0 if there is no prime number.
 func (c *Config) MaxMessageLen() int {
-	if max := len(c.Prime.Bytes()) - 1; max > 0 {
+	prime := c.prime()
+	if prime == nil {
+		return 0
+	}
+	if max := len(prime.Bytes()) - 1; max > 0 {
 		return max
 	}
 	return 0
@@ -44,27 +120,56 @@ func (c *Config) MaxMessageLen() int {
 
 // ValidPrime checks if the configuration has a valid prime number. It returns
 // an error if the prime number is not defined or if it is not a prime number.
+// If the name of the prime number is defined, it must be registered, and if
+// the prime number is defined too, with the same prime number. A name without
+// a prime number is enough to use the registered prime.
 func (c *Config) ValidPrime() error {
+	if c.PrimeName != "" {
+		prime, err := LookupPrime(c.PrimeName)
+		if err != nil {
+			return err
+		}
+		if c.Prime != nil && c.Prime.Cmp(prime) != 0 {
+			return ErrConfigPrimeName
+		}
+	}
 	// check if the prime number is a prime number
-	if c.Prime == nil {
+	prime := c.prime()
+	if prime == nil {
 		return ErrConfigNoPrime
 	}
-	if !c.Prime.ProbablyPrime(0) {
+	if !prime.ProbablyPrime(0) {
 		return ErrConfigInvalidPrime
 	}
-	if len(c.Prime.Bytes()) < 2 {
+	if len(prime.Bytes()) < 2 {
 		return ErrConfigInvalidPrime
 	}
 	return nil
 }
 
-// ValidConfig checks if the configuration is valid for the secret provided.
-// It checks if the number of shares is greater than the minimum number of
-// shares, if the minimum number of shares is greater than the number of shares
-// less one or if it is smaller than the minimum number of shares less one, if
-// the config has a valid prime number, and if the message can be hidden with
-// the prime number.
+// ValidConfig checks if the configuration is valid for the secret provided. It
+// checks if the number of shares is greater than the minimum number of shares,
+// if the minimum number of shares is greater than the number of shares less one
+// or if it is smaller than the minimum number of shares less one, if the config
+// has a valid prime number with at least the minimum number of bits required,
+// and if the message can be split into chunks that fit in the prime number. For
+// the GF(2^8) backend, it also checks that the number of shares fits in a byte.
+// If the field is defined, it is checked instead of the prime number, and its
+// elements must hold at least a byte of the message and the index of every
+// share. The encoding of the shares must be supported.
 func (c *Config) ValidConfig(secret []byte) error {
+	if c.Encoding != HexEncoding && c.Encoding != MnemonicEncoding {
+		return ErrConfigEncoding
+	}
+	switch c.Backend {
+	case PrimeBackend:
+	case GF256Backend:
+		if c.Shares > gf256MaxShares {
+			return ErrConfigShares
+		}
+	default:
+		return ErrConfigBackend
+	}
 	// check if the number of shares is greater than the minimum number of shares
 	if c.Shares < MinShares {
 		return ErrConfigShares
@@ -74,13 +179,104 @@ func (c *Config) ValidConfig(secret []byte) error {
 	if c.Min > c.Shares-1 || c.Min < MinMinShares {
 		return ErrConfigMin
 	}
+	// the field replaces the prime number, and only the prime backend can use
+	// it
+	if c.Field != nil {
+		if c.Backend != PrimeBackend {
+			return ErrConfigBackend
+		}
+		return validField(c.Field, c.Shares)
+	}
 	// check if the config has a valid prime number
 	if err := c.ValidPrime(); err != nil {
 		return err
 	}
-	// check if the message can be hidden with the prime number
-	if len(secret) > c.MaxMessageLen() {
+	// check if the prime number reaches the security level required
+	if c.prime().BitLen() < c.MinPrimeBits {
+		return ErrConfigPrimeTooSmall
+	}
+	// check if the message can be split into chunks smaller than the prime
+	// number
+	if len(secret) > 0 && c.MaxMessageLen() == 0 {
 		return ErrMessageTooLong
 	}
 	return nil
 }
+
+// selectPrime selects the name of the prime number to hide the message
+// provided if AutoPrime is set and the prime number is not defined by value or
+// by name. It returns an error if no registered prime number reaches the
+// minimum number of bits.
+func (c *Config) selectPrime(message []byte) error {
+	if !c.AutoPrime || c.Prime != nil || c.PrimeName != "" {
+		return nil
+	}
+	name, err := smallestPrime(len(message), c.MinPrimeBits)
+	if err != nil {
+		return err
+	}
+	c.PrimeName = name
+	return nil
+}
+
+// messageConfig returns a copy of the configuration provided prepared to hide
+// the message provided. If AutoPrime is set, the prime number is selected for
+// the message in the copy, so the configuration provided is not modified and
+// it selects the prime number again for the next message. It returns an error
+// if the configuration is not provided or if no registered prime number
+// reaches the minimum number of bits.
+func messageConfig(message []byte, conf *Config) (*Config, error) {
+	if conf == nil {
+		return nil, ErrRequiredConfig
+	}
+	prepared := *conf
+	if err := prepared.selectPrime(message); err != nil {
+		return nil, err
+	}
+	prepared.prepare()
+	return &prepared, nil
+}
+
+// sharesConfig returns a copy of the configuration provided, or an empty one
+// if it is not provided, prepared to be used with the shares provided. If the
+// configuration does not define the prime number or its name, it uses the name
+// embedded in the shares, so they can be recovered without knowing the prime
+// number.
+func sharesConfig(shares []Share, conf *Config) *Config {
+	prepared := &Config{}
+	if conf != nil {
+		*prepared = *conf
+	}
+	if prepared.Prime == nil && prepared.PrimeName == "" && len(shares) > 0 {
+		prepared.PrimeName = shares[0].PrimeName
+	}
+	prepared.prepare()
+	return prepared
+}
+
+// primeID returns the identifier of the prime number of the configuration, it
+// is the first bytes of the sha256 hash of the prime number. It is included in
+// the shares to detect if they are recovered with a different prime number.
+func (c *Config) primeID() []byte {
+	hash := sha256.Sum256(c.Prime.Bytes())
+	return hash[:primeIDLen]
+}
+
+// group returns the group of the configuration to commit to the polynomials.
+// If the group is not defined and the prime number is the default one, it
+// uses the G1 group of the bn254 curve, whose order is the default prime. It
+// returns an error if there is no group for the prime number or if the order
+// of the group does not match the prime number.
+func (c *Config) group() (Group, error) {
+	group := c.Group
+	if group == nil {
+		if c.Prime.Cmp(DefaultPrime) != 0 {
+			return nil, ErrConfigNoGroup
+		}
+		group = BN254G1
+	}
+	if group.Order().Cmp(c.Prime) != 0 {
+		return nil, ErrConfigGroupOrder
+	}
+	return group, nil
+}
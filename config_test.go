@@ -74,8 +74,8 @@ func TestValidConfig(t *testing.T) {
 		t.Errorf("expected error, got nil")
 	}
 	c.Prime = new(big.Int).SetUint64(10007)
-	if err := c.ValidConfig([]byte("12345")); err == nil {
-		t.Errorf("expected error, got nil")
+	if err := c.ValidConfig([]byte("12345")); err != nil {
+		t.Errorf("unexpected error: %v", err)
 	}
 }
 
@@ -89,3 +89,35 @@ func TestMaxMessageLen(t *testing.T) {
 		t.Errorf("expected 7, got %d", c.MaxMessageLen())
 	}
 }
+
+func TestConfigPrimeNameOnly(t *testing.T) {
+	prime, err := LookupPrime(PrimeSecp256k1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the prime number is resolved from its name without preparing the config
+	c := Config{Shares: 5, Min: 3, PrimeName: PrimeSecp256k1}
+	if err := c.ValidPrime(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := c.ValidConfig([]byte("message")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if expected := len(prime.Bytes()) - 1; c.MaxMessageLen() != expected {
+		t.Errorf("expected %d, got %d", expected, c.MaxMessageLen())
+	}
+	if c.Prime != nil {
+		t.Errorf("unexpected prime: %v", c.Prime)
+	}
+	// unknown names and configs without prime do not panic
+	c.PrimeName = "unknown"
+	if err := c.ValidPrime(); err != ErrConfigPrimeName {
+		t.Errorf("expected %v, got %v", ErrConfigPrimeName, err)
+	}
+	if c.MaxMessageLen() != 0 {
+		t.Errorf("expected 0, got %d", c.MaxMessageLen())
+	}
+	if max := (&Config{}).MaxMessageLen(); max != 0 {
+		t.Errorf("expected 0, got %d", max)
+	}
+}
@@ -1,20 +1,253 @@
 package gosss
 
 import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"math/big"
 )
 
+const (
+	// shareMagic is the first byte of every share encoded with the versioned
+	// binary format, followed by the version of the format.
+	shareMagic = 0x53
+	// shareVersion is the current version of the binary format of the shares,
+	// which includes the name of the prime number and the flags of the share.
+	// The previous versions, without the flags and without the name, can still
+	// be decoded.
+	shareVersion   = 0x03
+	shareVersionV2 = 0x02
+	shareVersionV1 = 0x01
+	// shareFlagBlinding is the flag of the blinding shares, see Share.
+	shareFlagBlinding = 0x01
+	// setIDLen is the length in bytes of the identifier of a set of shares.
+	setIDLen = 8
+	// primeIDLen is the length in bytes of the identifier of the prime number
+	// used to generate a set of shares.
+	primeIDLen = 4
+	// checksumLen is the length in bytes of the checksum appended to every
+	// share encoded with the versioned binary format.
+	checksumLen = 4
+)
+
+// newSetID generates a random identifier for a set of shares. It returns an
+// error if the random bytes cannot be generated.
+func newSetID() ([]byte, error) {
+	id := make([]byte, setIDLen)
+	if _, err := rand.Read(id); err != nil {
+		return nil, errors.Join(ErrReadingRandom, err)
+	}
+	return id, nil
+}
+
+// marshalShare encodes the share provided using the versioned binary format.
+// The format starts with the magic byte and the version, followed by the set
+// identifier and the prime identifier, which have a fixed length. Then the
+// name of the prime number, which can be empty, the threshold, the total
+// number of shares, the epoch, the flags of the share, the x coordinate and
+// the y coordinate of every chunk are encoded, using varints to prefix the
+// length of the name and of each coordinate and the number of chunks. Finally,
+// a checksum of all the previous bytes is appended to detect typos and
+// corrupted shares:
+//
+//	magic | version | setID | primeID | len(name) | name | threshold | total |
+//	epoch | flags | len(x) | x | nchunks | len(y_0) | y_0 | ... | len(y_n) |
+//	y_n | checksum
+//
+// The second version of the format is the same without the flags, and the
+// first one is also without the name of the prime.
+//
+// Legacy shares are encoded with the legacy format instead. It returns an
+// error if the share has no x coordinate, if its identifiers have a wrong
+// length or if it is a legacy blinding share, because the legacy format can
+// not mark it.
+func marshalShare(s *Share) ([]byte, error) {
+	if s.Index == nil {
+		return nil, ErrInvalidShare
+	}
+	if s.legacy() {
+		if len(s.Values) != 1 || s.Blinding {
+			return nil, ErrInvalidShare
+		}
+		return pointToBytes(s.Index, s.Values[0])
+	}
+	if len(s.SetID) != setIDLen || len(s.PrimeID) != primeIDLen {
+		return nil, ErrInvalidShare
+	}
+	b := []byte{shareMagic, shareVersion}
+	b = append(b, s.SetID...)
+	b = append(b, s.PrimeID...)
+	b = binary.AppendUvarint(b, uint64(len(s.PrimeName)))
+	b = append(b, s.PrimeName...)
+	b = binary.AppendUvarint(b, uint64(s.Threshold))
+	b = binary.AppendUvarint(b, uint64(s.Total))
+	b = binary.AppendUvarint(b, uint64(s.Epoch))
+	var flags uint64
+	if s.Blinding {
+		flags |= shareFlagBlinding
+	}
+	b = binary.AppendUvarint(b, flags)
+	bx := s.Index.Bytes()
+	b = binary.AppendUvarint(b, uint64(len(bx)))
+	b = append(b, bx...)
+	b = binary.AppendUvarint(b, uint64(len(s.Values)))
+	for _, y := range s.Values {
+		by := y.Bytes()
+		b = binary.AppendUvarint(b, uint64(len(by)))
+		b = append(b, by...)
+	}
+	return append(b, shareChecksum(b)...), nil
+}
+
+// unmarshalShare decodes a share encoded in binary. It accepts both the
+// versioned binary format and the legacy format, where the message is encoded
+// in a single point. It tries the versioned format first and falls back to the
+// legacy one if it cannot be decoded, because a legacy share could start with
+// the magic byte too. If the share starts with the magic byte and a known
+// version but its checksum does not match, it is a corrupted versioned share,
+// so the checksum error is returned without falling back, even if the bytes
+// could be decoded as a legacy point. If both fail, the error of the versioned
+// format is returned for the shares that start with the magic byte.
+func unmarshalShare(b []byte) (*Share, error) {
+	var versionErr error
+	if len(b) > 0 && b[0] == shareMagic {
+		s, err := unmarshalVersionedShare(b)
+		if err == nil {
+			return s, nil
+		}
+		if err == ErrShareChecksum {
+			return nil, err
+		}
+		versionErr = err
+	}
+	x, y, err := bytesToPoint(b)
+	if err != nil {
+		if versionErr != nil {
+			return nil, versionErr
+		}
+		return nil, err
+	}
+	return &Share{Index: x, Values: []*big.Int{y}}, nil
+}
+
+// unmarshalVersionedShare decodes a share encoded with the versioned binary
+// format. It returns an error if the magic byte does not match, if the version
+// is not supported, if the checksum does not match the content of the share or
+// if the share is malformed, including any trailing byte after the last chunk
+// or any unknown flag.
+func unmarshalVersionedShare(b []byte) (*Share, error) {
+	if len(b) < 2 || b[0] != shareMagic {
+		return nil, ErrInvalidShare
+	}
+	version := b[1]
+	if version != shareVersion && version != shareVersionV2 && version != shareVersionV1 {
+		return nil, ErrUnsupportedVersion
+	}
+	if len(b) < 2+checksumLen {
+		return nil, ErrInvalidShare
+	}
+	content, checksum := b[:len(b)-checksumLen], b[len(b)-checksumLen:]
+	if !bytes.Equal(checksum, shareChecksum(content)) {
+		return nil, ErrShareChecksum
+	}
+	r := &shareReader{b: content[2:]}
+	s := &Share{
+		SetID:   r.next(setIDLen),
+		PrimeID: r.next(primeIDLen),
+	}
+	if version != shareVersionV1 {
+		s.PrimeName = string(r.bytes())
+	}
+	s.Threshold = int(r.uvarint())
+	s.Total = int(r.uvarint())
+	s.Epoch = int(r.uvarint())
+	if version == shareVersion {
+		flags := r.uvarint()
+		if flags&^shareFlagBlinding != 0 {
+			return nil, ErrInvalidShare
+		}
+		s.Blinding = flags&shareFlagBlinding != 0
+	}
+	s.Index = new(big.Int).SetBytes(r.bytes())
+	nchunks := r.uvarint()
+	// every chunk needs at least one byte for its length, so limit the number
+	// of chunks by the remaining bytes to avoid large allocations
+	if nchunks > uint64(len(r.b)) {
+		return nil, ErrInvalidShare
+	}
+	s.Values = make([]*big.Int, 0, nchunks)
+	for i := uint64(0); i < nchunks; i++ {
+		s.Values = append(s.Values, new(big.Int).SetBytes(r.bytes()))
+	}
+	if r.err || len(r.b) != 0 {
+		return nil, ErrInvalidShare
+	}
+	return s, nil
+}
+
+// shareChecksum returns the checksum of the encoded share provided, it is the
+// first bytes of the sha256 hash of the share.
+func shareChecksum(b []byte) []byte {
+	hash := sha256.Sum256(b)
+	return hash[:checksumLen]
+}
+
+// shareReader struct helps to decode the fields of a share in order, keeping
+// the remaining bytes and a flag that is set if any field cannot be read, so
+// the error only has to be checked once after reading every field.
+type shareReader struct {
+	b   []byte
+	err bool
+}
+
+// next returns the next n bytes of the reader or nil if there are not enough
+// bytes.
+func (r *shareReader) next(n int) []byte {
+	if r.err || n > len(r.b) {
+		r.err = true
+		return nil
+	}
+	res := r.b[:n]
+	r.b = r.b[n:]
+	return res
+}
+
+// uvarint returns the next varint of the reader or zero if it cannot be
+// decoded.
+func (r *shareReader) uvarint() uint64 {
+	if r.err {
+		return 0
+	}
+	v, n := binary.Uvarint(r.b)
+	if n <= 0 {
+		r.err = true
+		return 0
+	}
+	r.b = r.b[n:]
+	return v
+}
+
+// bytes returns the next bytes of the reader prefixed by their length encoded
+// as a varint.
+func (r *shareReader) bytes() []byte {
+	l := r.uvarint()
+	if l > uint64(len(r.b)) {
+		r.err = true
+		return nil
+	}
+	return r.next(int(l))
+}
+
 // shareToStr converts a big.Int to a string. It uses the bytes of the big.Int
 func shareToStr(x, y *big.Int) (string, error) {
-	bx, by := x.Bytes(), y.Bytes()
-	lx, ly := len(bx), len(by)
-	if lx > 255 || ly > 255 {
-		return "", ErrShareTooLong
+	b, err := pointToBytes(x, y)
+	if err != nil {
+		return "", err
 	}
-	fullShare := append(bx, by...)
-	fullShare = append(fullShare, []byte{byte(lx), byte(ly)}...)
-	return hex.EncodeToString(fullShare), nil
+	return hex.EncodeToString(b), nil
 }
 
 // strToShare converts a string to a big.Int. It uses the bytes of the string
@@ -23,11 +256,73 @@ func strToShare(s string) (*big.Int, *big.Int, error) {
 	if err != nil {
 		return nil, nil, ErrInvalidShare
 	}
+	return bytesToPoint(b)
+}
+
+// pointToBytes encodes a point with the legacy format, which is the bytes of
+// the x coordinate followed by the bytes of the y coordinate and the length of
+// both coordinates in a single byte each. It returns an error if any
+// coordinate is longer than 255 bytes.
+func pointToBytes(x, y *big.Int) ([]byte, error) {
+	bx, by := x.Bytes(), y.Bytes()
+	lx, ly := len(bx), len(by)
+	if lx > 255 || ly > 255 {
+		return nil, ErrShareTooLong
+	}
+	fullShare := append(bx, by...)
+	return append(fullShare, []byte{byte(lx), byte(ly)}...), nil
+}
+
+// bytesToPoint decodes a point encoded with the legacy format. It returns an
+// error if the lengths of the coordinates do not match the provided bytes.
+func bytesToPoint(b []byte) (*big.Int, *big.Int, error) {
 	lb := len(b)
-	if len(b) < 2 {
+	if lb < 2 {
 		return nil, nil, ErrInvalidShare
 	}
 	lx, ly := int(b[lb-2]), int(b[lb-1])
+	if lx+ly != lb-2 {
+		return nil, nil, ErrInvalidShare
+	}
 	bx, by := b[:lx], b[lx:lx+ly]
 	return new(big.Int).SetBytes(bx), new(big.Int).SetBytes(by), nil
 }
+
+// messageToChunks splits the message into chunks of the size provided and
+// encodes each of them as a big.Int. To preserve the exact length of the
+// message, including any zero at the beginning of it, the message is prefixed
+// with its length encoded as a varint and padded with zeros up to a multiple
+// of the chunk size, so every chunk has the same size. An empty message
+// results in a single chunk that only includes its length.
+func messageToChunks(message []byte, size int) []*big.Int {
+	encoded := binary.AppendUvarint(nil, uint64(len(message)))
+	encoded = append(encoded, message...)
+	if rest := len(encoded) % size; rest != 0 {
+		encoded = append(encoded, make([]byte, size-rest)...)
+	}
+	chunks := make([]*big.Int, 0, len(encoded)/size)
+	for start := 0; start < len(encoded); start += size {
+		chunks = append(chunks, new(big.Int).SetBytes(encoded[start:start+size]))
+	}
+	return chunks
+}
+
+// chunksToMessage joins the chunks provided into the original message. Every
+// chunk is decoded to the full size provided, to keep the zeros at the
+// beginning of them, and the length prefix is used to remove the padding of
+// the message. It returns an error if any chunk does not fit in the size
+// provided or if the length prefix is not valid.
+func chunksToMessage(chunks []*big.Int, size int) ([]byte, error) {
+	encoded := make([]byte, 0, len(chunks)*size)
+	for _, chunk := range chunks {
+		if len(chunk.Bytes()) > size {
+			return nil, ErrDecodingMessage
+		}
+		encoded = append(encoded, chunk.FillBytes(make([]byte, size))...)
+	}
+	length, n := binary.Uvarint(encoded)
+	if n <= 0 || length > uint64(len(encoded)-n) {
+		return nil, ErrDecodingMessage
+	}
+	return encoded[n : n+int(length)], nil
+}
@@ -1,6 +1,8 @@
 package gosss
 
 import (
+	"bytes"
+	"encoding/binary"
 	"math/big"
 	"testing"
 )
@@ -50,3 +52,172 @@ func Test_shareToStrStrToShare(t *testing.T) {
 		t.Errorf("expected error, got nil")
 	}
 }
+
+func Test_messageToChunksChunksToMessage(t *testing.T) {
+	message := []byte("this message is longer than a single chunk")
+	chunks := messageToChunks(message, 8)
+	if len(chunks) != 6 {
+		t.Fatalf("unexpected number of chunks: %d", len(chunks))
+	}
+	recovered, err := chunksToMessage(chunks, 8)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(message, recovered) {
+		t.Errorf("unexpected message: %s", recovered)
+	}
+	// chunks starting with zeros
+	message = []byte{1, 2, 0, 0, 3, 4}
+	recovered, err = chunksToMessage(messageToChunks(message, 2), 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(message, recovered) {
+		t.Errorf("unexpected message: %v", recovered)
+	}
+	// empty message
+	if chunks := messageToChunks(nil, 8); len(chunks) != 1 || chunks[0].Sign() != 0 {
+		t.Errorf("unexpected chunks for empty message: %v", chunks)
+	}
+	// message starting with zeros
+	message = []byte{0, 0, 0, 1, 2}
+	recovered, err = chunksToMessage(messageToChunks(message, 4), 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(message, recovered) {
+		t.Errorf("unexpected message: %v", recovered)
+	}
+	// chunk that does not fit in the size
+	if _, err := chunksToMessage([]*big.Int{big.NewInt(1000)}, 1); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+	// length prefix longer than the chunks
+	if _, err := chunksToMessage([]*big.Int{big.NewInt(0x0901)}, 2); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func Test_marshalShareUnmarshalShare(t *testing.T) {
+	setID, err := newSetID()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := &Share{
+		SetID:     setID,
+		PrimeID:   []byte{1, 2, 3, 4},
+		PrimeName: PrimeSecp256k1,
+		Threshold: 3,
+		Total:     300,
+		Epoch:     2,
+		Index:     big.NewInt(257),
+		Values:    []*big.Int{big.NewInt(0), DefaultPrime, big.NewInt(12345)},
+	}
+	b, err := marshalShare(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ns, err := unmarshalShare(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(s.SetID, ns.SetID) || !bytes.Equal(s.PrimeID, ns.PrimeID) {
+		t.Errorf("unexpected identifiers: %x %x", ns.SetID, ns.PrimeID)
+	}
+	if s.PrimeName != ns.PrimeName {
+		t.Errorf("expected %s, got %s", s.PrimeName, ns.PrimeName)
+	}
+	if s.Threshold != ns.Threshold || s.Total != ns.Total || s.Epoch != ns.Epoch {
+		t.Errorf("unexpected threshold, total or epoch: %d %d %d", ns.Threshold, ns.Total, ns.Epoch)
+	}
+	if s.Index.Cmp(ns.Index) != 0 || len(s.Values) != len(ns.Values) {
+		t.Fatalf("unexpected points: %v %v", ns.Index, ns.Values)
+	}
+	for i := range s.Values {
+		if s.Values[i].Cmp(ns.Values[i]) != 0 {
+			t.Errorf("unexpected y coord: %d", ns.Values[i])
+		}
+	}
+	// the second version of the format has no flags, and the first one has no
+	// prime name either
+	s.PrimeName = ""
+	v3, err := marshalShare(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	header := 2 + setIDLen + primeIDLen
+	flagsPos := header + 1 + len(binary.AppendUvarint(nil, uint64(s.Threshold))) +
+		len(binary.AppendUvarint(nil, uint64(s.Total))) + len(binary.AppendUvarint(nil, uint64(s.Epoch)))
+	v2 := []byte{shareMagic, shareVersionV2}
+	v2 = append(v2, v3[2:flagsPos]...)
+	v2 = append(v2, v3[flagsPos+1:len(v3)-checksumLen]...)
+	v1 := []byte{shareMagic, shareVersionV1}
+	v1 = append(v1, v2[2:header]...)
+	v1 = append(v1, v2[header+1:]...)
+	for _, old := range [][]byte{v2, v1} {
+		old = append(old, shareChecksum(old)...)
+		if ns, err = unmarshalShare(old); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ns.PrimeName != "" || ns.Blinding || ns.Threshold != s.Threshold || ns.Index.Cmp(s.Index) != 0 {
+			t.Errorf("unexpected share: %s %d %v", ns.PrimeName, ns.Threshold, ns.Index)
+		}
+	}
+	// the blinding flag is kept and unknown flags are rejected
+	s.Blinding = true
+	blinding, err := marshalShare(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ns, err = unmarshalShare(blinding); err != nil || !ns.Blinding {
+		t.Errorf("unexpected blinding share: %+v %v", ns, err)
+	}
+	blinding = blinding[:len(blinding)-checksumLen]
+	blinding[flagsPos] = 0x02
+	blinding = append(blinding, shareChecksum(blinding)...)
+	if _, err := unmarshalVersionedShare(blinding); err != ErrInvalidShare {
+		t.Errorf("expected %v, got %v", ErrInvalidShare, err)
+	}
+	s.Blinding = false
+	// corrupted content
+	corrupted := bytes.Clone(b)
+	corrupted[len(corrupted)/2] ^= 0x01
+	if _, err := unmarshalVersionedShare(corrupted); err != ErrShareChecksum {
+		t.Errorf("expected %v, got %v", ErrShareChecksum, err)
+	}
+	// truncated, extended, unknown version and wrong magic byte
+	if _, err := unmarshalShare(b[:len(b)-1]); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+	if _, err := unmarshalShare(append(b, 1)); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+	b[1] = shareVersion + 1
+	if _, err := unmarshalShare(b); err != ErrUnsupportedVersion {
+		t.Errorf("expected %v, got %v", ErrUnsupportedVersion, err)
+	}
+	b[0] = shareMagic + 1
+	if _, err := unmarshalShare(b); err != ErrInvalidShare {
+		t.Errorf("expected %v, got %v", ErrInvalidShare, err)
+	}
+	// wrong identifiers length
+	s.SetID = s.SetID[1:]
+	if _, err := marshalShare(s); err != ErrInvalidShare {
+		t.Errorf("expected %v, got %v", ErrInvalidShare, err)
+	}
+	// legacy share, including one that starts with the magic byte
+	for _, x := range []int64{2, shareMagic} {
+		legacy := &Share{Index: big.NewInt(x), Values: []*big.Int{big.NewInt(3)}}
+		b, err := marshalShare(legacy)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ns, err := unmarshalShare(b)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !ns.legacy() || ns.Index.Int64() != x || ns.Values[0].Int64() != 3 {
+			t.Errorf("unexpected share: %v", ns)
+		}
+	}
+}
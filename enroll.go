@@ -0,0 +1,123 @@
+package gosss
+
+import (
+	"math/big"
+)
+
+// EnrollShare runs the first step of a holder of the quorum in the protocol
+// to enroll a new holder, generating a valid share for the new index without
+// reconstructing the message. The quorum is the list of the indexes of the
+// current holders that take part in the protocol, which must reach the
+// threshold of the set and include the index of the share provided. The
+// holder multiplies its share by its Lagrange basis polynomial evaluated at
+// the new index, so the contributions of the quorum add up to the point of the
+// new index, and splits the result into random pieces that add up to it, one
+// for every holder of the quorum, so the new holder never receives the
+// contribution of a single holder. It returns the pieces in the same order of
+// the quorum, and every piece must be sent only to the holder with the same
+// position, which adds up the pieces received with CombineEnrollmentShares and
+// sends the result to the new holder. The pieces have the new index and the
+// same metadata of the share, so the new share can be used with the existing
+// ones. The total number of shares of the metadata is kept as the number of
+// shares of the original split, so the new index can be greater than it. It
+// returns an error if the share is a legacy share, if the quorum is
+// not valid or if the new index is zero, is not an element of the finite
+// field or is part of the quorum.
+func EnrollShare(share Share, quorum []*big.Int, index *big.Int, conf *Config) ([]Share, error) {
+	conf = sharesConfig([]Share{share}, conf)
+	if err := conf.ValidPrime(); err != nil {
+		return nil, err
+	}
+	if err := checkShares([]Share{share}, conf); err != nil {
+		return nil, err
+	}
+	if share.legacy() {
+		return nil, ErrLegacyShare
+	}
+	position, err := quorumPosition(share, quorum)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNewIndex(index, quorum, conf); err != nil {
+		return nil, err
+	}
+	// every chunk of the share is multiplied by the Lagrange basis of the
+	// holder at the new index and masked splitting it in random pieces
+	basis := lagrangeBasis(quorum, position, conf.Prime, index)
+	pieces := make([]Share, len(quorum))
+	for i := range pieces {
+		pieces[i] = share
+		pieces[i].Index = new(big.Int).Set(index)
+		pieces[i].Values = make([]*big.Int, len(share.Values))
+	}
+	for j, value := range share.Values {
+		contribution := new(big.Int).Mul(value, basis)
+		contribution.Mod(contribution, conf.Prime)
+		// the last piece is the contribution minus the rest of the pieces
+		for i := 0; i < len(pieces)-1; i++ {
+			if pieces[i].Values[j], err = randFieldElement(conf.Prime); err != nil {
+				return nil, err
+			}
+			contribution.Sub(contribution, pieces[i].Values[j])
+		}
+		pieces[len(pieces)-1].Values[j] = contribution.Mod(contribution, conf.Prime)
+	}
+	return pieces, nil
+}
+
+// CombineEnrollmentShares adds up the shares received in the protocol to
+// enroll a new holder. It is used by every holder of the quorum to add up the
+// pieces received from the rest of the quorum, generated with EnrollShare,
+// and by the new holder to add up the results received from every holder of
+// the quorum, which is its new share. Every piece or result of the quorum is
+// required, otherwise the new share will not be valid. It uses the
+// configuration provided in the Config struct, if the prime number is not
+// defined it uses the bn254 𝔽r prime as default. It returns an error if no
+// share is provided or if they are not for the same index and set.
+func CombineEnrollmentShares(shares []Share, conf *Config) (Share, error) {
+	return addShares(shares, conf)
+}
+
+// EnrollShares generates the share of a new holder with the index provided
+// running every step of the enrollment protocol locally: every share provided
+// is part of the quorum and generates its pieces with EnrollShare, every
+// holder adds up the pieces received and the new holder adds up the results
+// with CombineEnrollmentShares. As RedistributeShares, it is only meant for a
+// party that already has access to the quorum. It returns the new share or an
+// error if any step fails.
+func EnrollShares(shares []Share, index *big.Int, conf *Config) (Share, error) {
+	quorum := sharesIndexes(shares)
+	received := make([][]Share, len(shares))
+	for _, share := range shares {
+		pieces, err := EnrollShare(share, quorum, index, conf)
+		if err != nil {
+			return Share{}, err
+		}
+		for i, piece := range pieces {
+			received[i] = append(received[i], piece)
+		}
+	}
+	results := make([]Share, len(received))
+	for i, pieces := range received {
+		var err error
+		if results[i], err = CombineEnrollmentShares(pieces, conf); err != nil {
+			return Share{}, err
+		}
+	}
+	return CombineEnrollmentShares(results, conf)
+}
+
+// checkNewIndex checks that the index provided can be used for a new share
+// generated by the quorum provided. It returns an error if the index is nil,
+// zero, not an element of the finite field or already part of the quorum.
+func checkNewIndex(index *big.Int, quorum []*big.Int, conf *Config) error {
+	if index == nil || index.Sign() <= 0 || index.Cmp(conf.Prime) >= 0 {
+		return ErrInvalidIndex
+	}
+	for _, x := range quorum {
+		if x != nil && x.Cmp(index) == 0 {
+			return ErrInvalidIndex
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package gosss
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestEnrollShares(t *testing.T) {
+	config := &Config{
+		Shares: 5,
+		Min:    3,
+	}
+	message := bytes.Repeat(examplePrivateMessage, 3)
+	shares, err := HideMessageShares(message, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	index := big.NewInt(123456789)
+	newShare, err := EnrollShares(shares[2:], index, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if newShare.Index.Cmp(index) != 0 || newShare.Epoch != shares[0].Epoch {
+		t.Errorf("unexpected share: %v %d", newShare.Index, newShare.Epoch)
+	}
+	// the new share can be used with the existing ones
+	recovered, err := RecoverMessageShares([]Share{newShare, shares[0], shares[1]}, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(recovered, message) {
+		t.Errorf("unexpected message: %s", recovered)
+	}
+	// the new share lies on the same polynomials of the existing ones
+	report, err := VerifyConsistency(append([]Share{newShare}, shares...), config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.Ok() {
+		t.Errorf("unexpected inconsistent shares: %v", report.Inconsistent)
+	}
+}
+
+func TestEnrollShare(t *testing.T) {
+	config := &Config{
+		Shares: 4,
+		Min:    3,
+	}
+	shares, err := HideMessageShares(examplePrivateMessage, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	quorum := []*big.Int{shares[0].Index, shares[1].Index, shares[2].Index}
+	for _, index := range []*big.Int{nil, big.NewInt(0), big.NewInt(-1), DefaultPrime, shares[1].Index} {
+		if _, err := EnrollShare(shares[0], quorum, index, config); err != ErrInvalidIndex {
+			t.Errorf("expected %v, got %v", ErrInvalidIndex, err)
+		}
+	}
+	if _, err := EnrollShare(shares[3], quorum, big.NewInt(100), config); err != ErrNotInQuorum {
+		t.Errorf("expected %v, got %v", ErrNotInQuorum, err)
+	}
+	// the pieces of a holder do not reveal its contribution but add up to it
+	index := big.NewInt(100)
+	pieces, err := EnrollShare(shares[0], quorum, index, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pieces) != len(quorum) {
+		t.Fatalf("unexpected number of pieces: %d", len(pieces))
+	}
+	sum, err := CombineEnrollmentShares(pieces, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	basis := lagrangeBasis(quorum, 0, DefaultPrime, index)
+	for i, value := range shares[0].Values {
+		expected := new(big.Int).Mul(value, basis)
+		expected.Mod(expected, DefaultPrime)
+		if sum.Values[i].Cmp(expected) != 0 {
+			t.Errorf("expected %v, got %v", expected, sum.Values[i])
+		}
+	}
+}
@@ -2,17 +2,70 @@ package gosss
 
 import "fmt"
 
+// ShareError struct wraps an error produced by one of the shares provided to
+// recover a message, including the position of the share in the input, so
+// the corrupted share can be identified.
+type ShareError struct {
+	Position int
+	Err      error
+}
+
+// Error returns the message of the wrapped error including the position of
+// the share.
+func (e *ShareError) Error() string {
+	return fmt.Sprintf("share %d: %v", e.Position, e.Err)
+}
+
+// Unwrap returns the wrapped error, so it can be checked with errors.Is.
+func (e *ShareError) Unwrap() error {
+	return e.Err
+}
+
 var (
 	// config
-	ErrRequiredConfig     = fmt.Errorf("configuration is required")
-	ErrConfigShares       = fmt.Errorf("wrong number of shares")
-	ErrConfigMin          = fmt.Errorf("wrong minimum number of shares")
-	ErrConfigNoPrime      = fmt.Errorf("no prime provided")
-	ErrConfigInvalidPrime = fmt.Errorf("invalid prime provided")
-	ErrMessageTooLong     = fmt.Errorf("the message cannot be hidden with the prime provided")
+	ErrRequiredConfig      = fmt.Errorf("configuration is required")
+	ErrConfigShares        = fmt.Errorf("wrong number of shares")
+	ErrConfigMin           = fmt.Errorf("wrong minimum number of shares")
+	ErrConfigNoPrime       = fmt.Errorf("no prime provided")
+	ErrConfigInvalidPrime  = fmt.Errorf("invalid prime provided")
+	ErrMessageTooLong      = fmt.Errorf("the message cannot be hidden with the prime provided")
+	ErrConfigBackend       = fmt.Errorf("unknown backend provided")
+	ErrConfigPrimeName     = fmt.Errorf("unknown prime name or it does not match the prime provided")
+	ErrConfigPrimeTooSmall = fmt.Errorf("the prime does not reach the minimum number of bits")
+	ErrConfigEncoding      = fmt.Errorf("unsupported share encoding")
+	ErrConfigField         = fmt.Errorf("the field provided cannot hide the message or the shares")
+	ErrUnsupportedBackend  = fmt.Errorf("the backend does not support this operation")
 	// encode
-	ErrShareTooLong = fmt.Errorf("error encoding share, it is too long")
-	ErrInvalidShare = fmt.Errorf("error decoding share, it is invalid")
+	ErrShareTooLong       = fmt.Errorf("error encoding share, it is too long")
+	ErrInvalidShare       = fmt.Errorf("error decoding share, it is invalid")
+	ErrDecodingMessage    = fmt.Errorf("error decoding message from shares")
+	ErrUnsupportedVersion = fmt.Errorf("error decoding share, unsupported version")
+	ErrShareChecksum      = fmt.Errorf("error decoding share, checksum mismatch")
+	ErrTruncatedStream    = fmt.Errorf("error decoding share stream, it ended unexpectedly")
+	ErrInvalidMnemonic    = fmt.Errorf("error decoding share, invalid mnemonic word or length")
+	ErrMnemonicChecksum   = fmt.Errorf("error decoding share, mnemonic checksum mismatch")
+	// recover
+	ErrNotEnoughShares     = fmt.Errorf("not enough shares to recover the message")
+	ErrDuplicatedShare     = fmt.Errorf("duplicated share provided")
+	ErrShareSetMismatch    = fmt.Errorf("shares belong to different sets")
+	ErrPrimeMismatch       = fmt.Errorf("shares were not generated with the prime provided")
+	ErrTooManyFaultyShares = fmt.Errorf("too many faulty shares to recover the message")
+	ErrEpochMismatch       = fmt.Errorf("shares belong to different epochs")
+	ErrLegacyShare         = fmt.Errorf("legacy shares do not support this operation")
+	ErrNotInQuorum         = fmt.Errorf("the share is not part of the quorum")
+	ErrInvalidIndex        = fmt.Errorf("invalid index for the new share")
+	ErrBlindingShare       = fmt.Errorf("blinding shares can not be used to recover the message")
+	// blob
+	ErrInvalidEnvelope    = fmt.Errorf("invalid envelope provided")
+	ErrBlobAuthentication = fmt.Errorf("error decrypting the blob, authentication failed")
+	// verifiable secret sharing
+	ErrConfigNoGroup       = fmt.Errorf("no group provided for the prime provided")
+	ErrConfigGroupOrder    = fmt.Errorf("the group order does not match the prime provided")
+	ErrInvalidGroup        = fmt.Errorf("invalid group parameters provided")
+	ErrInvalidGroupElement = fmt.Errorf("invalid group element")
+	ErrInvalidCommitments  = fmt.Errorf("invalid commitments provided")
+	ErrShareNotVerified    = fmt.Errorf("share does not match the commitments")
 	// math
-	ErrReadingRandom = fmt.Errorf("error reading random number")
+	ErrReadingRandom       = fmt.Errorf("error reading random number")
+	ErrInvalidFieldElement = fmt.Errorf("invalid field element")
 )
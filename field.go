@@ -0,0 +1,335 @@
+package gosss
+
+import (
+	"bytes"
+	"math/big"
+)
+
+// Field interface defines the arithmetic of a finite field used to hide and
+// recover the messages. The elements of the field are represented as big.Int
+// values, so the polynomial and interpolation code is shared by every field,
+// and every implementation must keep the results in the range of its elements.
+// The inverse of zero is zero. The elements are encoded with a fixed length,
+// and decoding returns an error if the bytes are not a valid element.
+type Field interface {
+	Add(a, b *big.Int) *big.Int
+	Sub(a, b *big.Int) *big.Int
+	Mul(a, b *big.Int) *big.Int
+	Inv(a *big.Int) *big.Int
+	Rand() (*big.Int, error)
+	Encode(a *big.Int) []byte
+	Decode(b []byte) (*big.Int, error)
+}
+
+// PrimeField struct implements the Field interface for the finite field
+// defined by a prime number, where the operations are performed modulo the
+// prime.
+type PrimeField struct {
+	prime *big.Int
+}
+
+// NewPrimeField returns the finite field defined by the prime number
+// provided. It returns an error if the prime number is not valid.
+func NewPrimeField(prime *big.Int) (*PrimeField, error) {
+	if err := (&Config{Prime: prime}).ValidPrime(); err != nil {
+		return nil, err
+	}
+	return &PrimeField{prime: prime}, nil
+}
+
+// Prime returns the prime number that defines the finite field.
+func (f *PrimeField) Prime() *big.Int {
+	return f.prime
+}
+
+// Add returns a + b modulo the prime.
+func (f *PrimeField) Add(a, b *big.Int) *big.Int {
+	r := new(big.Int).Add(a, b)
+	return r.Mod(r, f.prime)
+}
+
+// Sub returns a - b modulo the prime.
+func (f *PrimeField) Sub(a, b *big.Int) *big.Int {
+	r := new(big.Int).Sub(a, b)
+	return r.Mod(r, f.prime)
+}
+
+// Mul returns a * b modulo the prime.
+func (f *PrimeField) Mul(a, b *big.Int) *big.Int {
+	r := new(big.Int).Mul(a, b)
+	return r.Mod(r, f.prime)
+}
+
+// Inv returns the modular inverse of a, or zero if a is zero modulo the prime.
+func (f *PrimeField) Inv(a *big.Int) *big.Int {
+	r := new(big.Int).Mod(a, f.prime)
+	if r.Sign() == 0 {
+		return r
+	}
+	return r.ModInverse(r, f.prime)
+}
+
+// Rand returns a random element of the field. It returns an error if the
+// random number cannot be generated.
+func (f *PrimeField) Rand() (*big.Int, error) {
+	return randFieldElement(f.prime)
+}
+
+// Encode returns the big-endian encoding of the element, padded to the size
+// of the prime in bytes.
+func (f *PrimeField) Encode(a *big.Int) []byte {
+	return new(big.Int).Mod(a, f.prime).FillBytes(make([]byte, len(f.prime.Bytes())))
+}
+
+// Decode returns the element encoded in the bytes provided. It returns an
+// error if the length does not match the size of the prime in bytes or if
+// the value is not smaller than the prime.
+func (f *PrimeField) Decode(b []byte) (*big.Int, error) {
+	if len(b) != len(f.prime.Bytes()) {
+		return nil, ErrInvalidFieldElement
+	}
+	a := new(big.Int).SetBytes(b)
+	if a.Cmp(f.prime) >= 0 {
+		return nil, ErrInvalidFieldElement
+	}
+	return a, nil
+}
+
+// GF256 is the finite field GF(2^8) with the AES reduction polynomial, used by
+// the GF(2^8) backend. Its elements are the big.Int values from 0 to 255.
+var GF256 Field = gf256Field{}
+
+// gf256Field struct implements the Field interface for GF(2^8) using the
+// exponential and logarithm tables of the field.
+type gf256Field struct{}
+
+// Add returns a + b in GF(2^8), which is the XOR of the elements.
+func (gf256Field) Add(a, b *big.Int) *big.Int {
+	return big.NewInt(int64(gf256Byte(a) ^ gf256Byte(b)))
+}
+
+// Sub returns a - b in GF(2^8), which is the same as the addition.
+func (gf256Field) Sub(a, b *big.Int) *big.Int {
+	return big.NewInt(int64(gf256Byte(a) ^ gf256Byte(b)))
+}
+
+// Mul returns a * b in GF(2^8).
+func (gf256Field) Mul(a, b *big.Int) *big.Int {
+	return big.NewInt(int64(gf256Mul(gf256Byte(a), gf256Byte(b))))
+}
+
+// Inv returns the inverse of a in GF(2^8), or zero if a is zero.
+func (gf256Field) Inv(a *big.Int) *big.Int {
+	if gf256Byte(a) == 0 {
+		return big.NewInt(0)
+	}
+	return big.NewInt(int64(gf256Div(1, gf256Byte(a))))
+}
+
+// Rand returns a random element of GF(2^8). It returns an error if the random
+// byte cannot be generated.
+func (gf256Field) Rand() (*big.Int, error) {
+	return randBigInt(1, nil)
+}
+
+// Encode returns the element as a single byte.
+func (gf256Field) Encode(a *big.Int) []byte {
+	return []byte{gf256Byte(a)}
+}
+
+// Decode returns the element encoded in a single byte. It returns an error if
+// the length is not one byte.
+func (gf256Field) Decode(b []byte) (*big.Int, error) {
+	if len(b) != 1 {
+		return nil, ErrInvalidFieldElement
+	}
+	return big.NewInt(int64(b[0])), nil
+}
+
+// gf256Byte returns the element of GF(2^8) provided as a byte, keeping only
+// its lowest eight bits.
+func gf256Byte(a *big.Int) byte {
+	return byte(a.Uint64())
+}
+
+// evalPolynomial evaluates the polynomial with the coefficients provided at x
+// in the field provided, using the Horner's method.
+func evalPolynomial(f Field, coeffs []*big.Int, x *big.Int) *big.Int {
+	accum := big.NewInt(0)
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		accum = f.Add(f.Mul(accum, x), coeffs[i])
+	}
+	return accum
+}
+
+// fieldLagrangeBasis calculates the Lagrange basis polynomial of the point i
+// of the x coordinates provided, evaluated at x, in the field provided.
+func fieldLagrangeBasis(f Field, xCoords []*big.Int, i int, x *big.Int) *big.Int {
+	numerator := big.NewInt(1)
+	denominator := big.NewInt(1)
+	for j := range xCoords {
+		if i == j {
+			continue
+		}
+		numerator = f.Mul(numerator, f.Sub(x, xCoords[j]))
+		denominator = f.Mul(denominator, f.Sub(xCoords[i], xCoords[j]))
+	}
+	return f.Mul(numerator, f.Inv(denominator))
+}
+
+// interpolatePolynomial calculates the Lagrange interpolation of the points
+// provided at x in the field provided, adding the y coordinate of every point
+// multiplied by its Lagrange basis polynomial.
+func interpolatePolynomial(f Field, xCoords, yCoords []*big.Int, x *big.Int) *big.Int {
+	result := big.NewInt(0)
+	for i := range xCoords {
+		result = f.Add(result, f.Mul(yCoords[i], fieldLagrangeBasis(f, xCoords, i, x)))
+	}
+	return result
+}
+
+// fieldElementLen returns the length in bytes of the encoded elements of the
+// field provided.
+func fieldElementLen(f Field) int {
+	return len(f.Encode(big.NewInt(0)))
+}
+
+// fieldChunkLen returns the size of the chunks of the message that fit in an
+// element of the field provided. If every value of the encoded length is a
+// valid element, it is the full length, otherwise it is one byte less, so
+// every chunk is smaller than the size of the field. It returns zero if no
+// byte of the message fits in an element.
+func fieldChunkLen(f Field) int {
+	size := fieldElementLen(f)
+	if size == 0 {
+		return 0
+	}
+	if _, err := f.Decode(bytes.Repeat([]byte{0xff}, size)); err == nil {
+		return size
+	}
+	return size - 1
+}
+
+// fieldIndex returns the element of the field provided that is the x
+// coordinate of the share with the index provided. It returns an error if the
+// index does not fit in an element of the field.
+func fieldIndex(f Field, index int) (*big.Int, error) {
+	x := big.NewInt(int64(index))
+	size := fieldElementLen(f)
+	if (x.BitLen()+7)/8 > size {
+		return nil, ErrConfigShares
+	}
+	return f.Decode(x.FillBytes(make([]byte, size)))
+}
+
+// validField checks if the field provided can hide a message in the number of
+// shares provided. It returns an error if no byte of the message fits in an
+// element of the field or if the index of any share is not an element of it.
+func validField(f Field, shares int) error {
+	if fieldChunkLen(f) < 1 {
+		return ErrConfigField
+	}
+	if _, err := fieldIndex(f, shares); err != nil {
+		return ErrConfigField
+	}
+	return nil
+}
+
+// hideMessageField generates the shares of the message over the field of the
+// configuration, which must be prepared. The message is split into chunks
+// that fit in an element of the field and every share is the encoded index of
+// the share followed by the encoded points of every chunk, encoded as a
+// hexadecimal string or as a mnemonic. It returns an error if the
+// configuration is not valid.
+func hideMessageField(message []byte, conf *Config) ([]string, error) {
+	if err := conf.ValidConfig(message); err != nil {
+		return nil, err
+	}
+	f := conf.Field
+	size := fieldElementLen(f)
+	xs := make([]*big.Int, conf.Shares)
+	shares := make([][]byte, conf.Shares)
+	for i := range xs {
+		var err error
+		if xs[i], err = fieldIndex(f, i+1); err != nil {
+			return nil, ErrConfigField
+		}
+		shares[i] = f.Encode(xs[i])
+	}
+	coeffs := make([]*big.Int, conf.Min)
+	for _, chunk := range messageToChunks(message, fieldChunkLen(f)) {
+		var err error
+		if coeffs[0], err = f.Decode(chunk.FillBytes(make([]byte, size))); err != nil {
+			return nil, ErrConfigField
+		}
+		for c := 1; c < len(coeffs); c++ {
+			if coeffs[c], err = f.Rand(); err != nil {
+				return nil, err
+			}
+		}
+		for i, x := range xs {
+			shares[i] = append(shares[i], f.Encode(evalPolynomial(f, coeffs, x))...)
+		}
+	}
+	strShares := make([]string, len(shares))
+	for i, share := range shares {
+		strShares[i] = encodeShareText(share, conf.Encoding)
+	}
+	return strShares, nil
+}
+
+// recoverMessageField recovers the message from the shares provided as
+// hexadecimal strings or mnemonics over the field of the configuration,
+// interpolating the polynomial of every chunk at x = 0. As the shares do not
+// include the threshold, the minimum number of shares of the configuration is
+// used to check that there are enough shares. If any share cannot be decoded,
+// has a zero or duplicated index or a different number of chunks, it returns
+// a ShareError with the position of the share in the input.
+func recoverMessageField(inputs []string, conf *Config) ([]byte, error) {
+	f := conf.Field
+	size, chunkLen := fieldElementLen(f), fieldChunkLen(f)
+	if chunkLen < 1 {
+		return nil, ErrConfigField
+	}
+	if len(inputs) == 0 || len(inputs) < conf.Min {
+		return nil, ErrNotEnoughShares
+	}
+	xs := make([]*big.Int, len(inputs))
+	points := make([][]*big.Int, len(inputs))
+	seen := map[string]bool{}
+	for i, input := range inputs {
+		b, err := decodeShareText(input)
+		if err != nil {
+			return nil, &ShareError{Position: i, Err: err}
+		}
+		if len(b) < 2*size || len(b)%size != 0 || (i > 0 && len(b) != size*(len(points[0])+1)) {
+			return nil, &ShareError{Position: i, Err: ErrInvalidShare}
+		}
+		elements := make([]*big.Int, 0, len(b)/size)
+		for start := 0; start < len(b); start += size {
+			element, err := f.Decode(b[start : start+size])
+			if err != nil {
+				return nil, &ShareError{Position: i, Err: ErrInvalidShare}
+			}
+			elements = append(elements, element)
+		}
+		xs[i], points[i] = elements[0], elements[1:]
+		if xs[i].Sign() == 0 {
+			return nil, &ShareError{Position: i, Err: ErrInvalidShare}
+		}
+		if seen[xs[i].String()] {
+			return nil, &ShareError{Position: i, Err: ErrDuplicatedShare}
+		}
+		seen[xs[i].String()] = true
+	}
+	chunks := make([]*big.Int, len(points[0]))
+	ys := make([]*big.Int, len(inputs))
+	for j := range chunks {
+		for i := range points {
+			ys[i] = points[i][j]
+		}
+		secret := interpolatePolynomial(f, xs, ys, big.NewInt(0))
+		chunks[j] = new(big.Int).SetBytes(f.Encode(secret))
+	}
+	return chunksToMessage(chunks, chunkLen)
+}
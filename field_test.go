@@ -0,0 +1,184 @@
+package gosss
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestNewPrimeField(t *testing.T) {
+	if _, err := NewPrimeField(big.NewInt(10008)); err != ErrConfigInvalidPrime {
+		t.Errorf("expected %v, got %v", ErrConfigInvalidPrime, err)
+	}
+	if _, err := NewPrimeField(nil); err != ErrConfigNoPrime {
+		t.Errorf("expected %v, got %v", ErrConfigNoPrime, err)
+	}
+	field, err := NewPrimeField(big.NewInt(10007))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if field.Prime().Int64() != 10007 {
+		t.Errorf("unexpected prime: %v", field.Prime())
+	}
+}
+
+func TestFields(t *testing.T) {
+	primeField, err := NewPrimeField(DefaultPrime)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, field := range []Field{primeField, GF256} {
+		for range 20 {
+			a, err := field.Rand()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			b, err := field.Rand()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if r := field.Sub(field.Add(a, b), b); r.Cmp(a) != 0 {
+				t.Errorf("expected %v, got %v", a, r)
+			}
+			if b.Sign() != 0 {
+				if r := field.Mul(field.Mul(a, b), field.Inv(b)); r.Cmp(a) != 0 {
+					t.Errorf("expected %v, got %v", a, r)
+				}
+			}
+			decoded, err := field.Decode(field.Encode(a))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if decoded.Cmp(a) != 0 {
+				t.Errorf("expected %v, got %v", a, decoded)
+			}
+		}
+		if r := field.Inv(big.NewInt(0)); r.Sign() != 0 {
+			t.Errorf("expected 0, got %v", r)
+		}
+		if _, err := field.Decode(nil); err != ErrInvalidFieldElement {
+			t.Errorf("expected %v, got %v", ErrInvalidFieldElement, err)
+		}
+	}
+	// the prime itself is not an element of the field
+	if _, err := primeField.Decode(DefaultPrime.Bytes()); err != ErrInvalidFieldElement {
+		t.Errorf("expected %v, got %v", ErrInvalidFieldElement, err)
+	}
+}
+
+func Test_interpolatePolynomial(t *testing.T) {
+	// the same polynomial code works in every field
+	primeField, _ := NewPrimeField(big.NewInt(10007))
+	for _, field := range []Field{primeField, GF256} {
+		coeffs := []*big.Int{big.NewInt(42), big.NewInt(7), big.NewInt(200)}
+		xs := []*big.Int{big.NewInt(3), big.NewInt(9), big.NewInt(17)}
+		ys := make([]*big.Int, len(xs))
+		for i, x := range xs {
+			ys[i] = evalPolynomial(field, coeffs, x)
+		}
+		if r := interpolatePolynomial(field, xs, ys, big.NewInt(0)); r.Cmp(coeffs[0]) != 0 {
+			t.Errorf("expected %v, got %v", coeffs[0], r)
+		}
+	}
+}
+
+// gf65536Field struct implements the Field interface for GF(2^16) with the
+// reduction polynomial x^16 + x^5 + x^3 + x + 1, to test the fields that are
+// not defined in the package.
+type gf65536Field struct{}
+
+func (gf65536Field) Add(a, b *big.Int) *big.Int {
+	return big.NewInt(int64(a.Uint64() ^ b.Uint64()))
+}
+
+func (f gf65536Field) Sub(a, b *big.Int) *big.Int {
+	return f.Add(a, b)
+}
+
+func (gf65536Field) Mul(a, b *big.Int) *big.Int {
+	x, y, r := a.Uint64(), b.Uint64(), uint64(0)
+	for ; y > 0; y >>= 1 {
+		if y&1 == 1 {
+			r ^= x
+		}
+		if x <<= 1; x&0x10000 != 0 {
+			x ^= 0x1002b
+		}
+	}
+	return big.NewInt(int64(r))
+}
+
+// Inv returns a^(2^16-2), which is the inverse of a or zero if a is zero.
+func (f gf65536Field) Inv(a *big.Int) *big.Int {
+	r := big.NewInt(1)
+	for i := 0; i < 15; i++ {
+		r = f.Mul(f.Mul(r, r), a)
+	}
+	return f.Mul(r, r)
+}
+
+func (gf65536Field) Rand() (*big.Int, error) {
+	return randBigInt(2, nil)
+}
+
+func (gf65536Field) Encode(a *big.Int) []byte {
+	return a.FillBytes(make([]byte, 2))
+}
+
+func (gf65536Field) Decode(b []byte) (*big.Int, error) {
+	if len(b) != 2 {
+		return nil, ErrInvalidFieldElement
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func TestHideRecoverField(t *testing.T) {
+	secp256k1, err := LookupPrime(PrimeSecp256k1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	scalarField, err := NewPrimeField(secp256k1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	message := []byte("message hidden in other fields")
+	for _, field := range []Field{gf65536Field{}, scalarField, GF256} {
+		for _, encoding := range []Encoding{HexEncoding, MnemonicEncoding} {
+			conf := &Config{Shares: 6, Min: 4, Field: field, Encoding: encoding}
+			shares, err := HideMessage(message, conf)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			recovered, err := RecoverMessage([]string{shares[5], shares[1], shares[3], shares[0]}, conf)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(recovered, message) {
+				t.Errorf("expected %s, got %s", message, recovered)
+			}
+			// fewer shares than the threshold
+			if _, err := RecoverMessage(shares[:3], conf); err != ErrNotEnoughShares {
+				t.Errorf("expected %v, got %v", ErrNotEnoughShares, err)
+			}
+			// duplicated share
+			_, err = RecoverMessage([]string{shares[0], shares[1], shares[2], shares[1]}, conf)
+			var shareErr *ShareError
+			if !errors.As(err, &shareErr) || shareErr.Position != 3 || !errors.Is(err, ErrDuplicatedShare) {
+				t.Errorf("expected %v at position 3, got %v", ErrDuplicatedShare, err)
+			}
+		}
+	}
+	// the field must hold the index of every share
+	if _, err := HideMessage(message, &Config{Shares: 300, Min: 3, Field: GF256}); err != ErrConfigField {
+		t.Errorf("expected %v, got %v", ErrConfigField, err)
+	}
+	// the field replaces the prime backend
+	if _, err := HideMessage(message, &Config{Shares: 5, Min: 3, Field: GF256, Backend: GF256Backend}); err != ErrConfigBackend {
+		t.Errorf("expected %v, got %v", ErrConfigBackend, err)
+	}
+	// other operations do not support the field
+	if _, err := HideMessageShares(message, &Config{Shares: 5, Min: 3, Field: GF256}); err != ErrUnsupportedBackend {
+		t.Errorf("expected %v, got %v", ErrUnsupportedBackend, err)
+	}
+}
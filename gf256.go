@@ -0,0 +1,165 @@
+package gosss
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+)
+
+// gf256MaxShares is the maximum number of shares of the GF(2^8) backend, every
+// share needs a different non-zero x coordinate that fits in a byte.
+const gf256MaxShares = 255
+
+// gf256Exp and gf256Log are the exponential and logarithm tables of GF(2^8)
+// with the AES reduction polynomial (x^8 + x^4 + x^3 + x + 1) and the
+// generator 3. The exponential table is doubled to avoid reducing the sum of
+// two logarithms.
+var gf256Exp, gf256Log = gf256Tables()
+
+// gf256Tables calculates the exponential and logarithm tables of GF(2^8).
+func gf256Tables() ([510]byte, [256]byte) {
+	var exp [510]byte
+	var log [256]byte
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		exp[i], exp[i+255] = x, x
+		log[x] = byte(i)
+		// multiply by the generator: x * 3 = x * 2 + x
+		doubled := x << 1
+		if x&0x80 != 0 {
+			doubled ^= 0x1b
+		}
+		x ^= doubled
+	}
+	return exp, log
+}
+
+// gf256Mul multiplies two elements of GF(2^8).
+func gf256Mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gf256Exp[int(gf256Log[a])+int(gf256Log[b])]
+}
+
+// gf256Div divides two elements of GF(2^8). The divisor must not be zero.
+func gf256Div(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gf256Exp[int(gf256Log[a])+255-int(gf256Log[b])]
+}
+
+// splitGF256 splits the secret into the number of shares provided, any k of
+// them can recover it. Every byte of the secret is the first coefficient of
+// its own random polynomial of degree k - 1 over GF(2^8). Every share
+// contains the evaluation of every polynomial at the x coordinate of the
+// share, followed by the x coordinate, so it is one byte longer than the
+// secret. The x coordinates are {1, ..., n}. It returns an error if the
+// random coefficients cannot be generated.
+func splitGF256(secret []byte, n, k int) ([][]byte, error) {
+	random := make([]byte, len(secret)*(k-1))
+	if _, err := rand.Read(random); err != nil {
+		return nil, errors.Join(ErrReadingRandom, err)
+	}
+	shares := make([][]byte, n)
+	for i := range shares {
+		shares[i] = make([]byte, len(secret)+1)
+		shares[i][len(secret)] = byte(i + 1)
+	}
+	coeffs := make([]*big.Int, k)
+	for j, b := range secret {
+		coeffs[0] = big.NewInt(int64(b))
+		for c, r := range random[j*(k-1) : (j+1)*(k-1)] {
+			coeffs[c+1] = big.NewInt(int64(r))
+		}
+		for i := range shares {
+			y := evalPolynomial(GF256, coeffs, big.NewInt(int64(i+1)))
+			shares[i][j] = gf256Byte(y)
+		}
+	}
+	return shares, nil
+}
+
+// combineGF256 recovers the secret from the shares provided, generated with
+// splitGF256, interpolating the polynomial of every byte at x = 0. It returns
+// an error if no share is provided, if the shares have different lengths or
+// if any x coordinate is zero or duplicated. The shares do not include the
+// threshold, so if fewer shares than the threshold are provided, the result
+// is a random secret.
+func combineGF256(shares [][]byte) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, ErrNotEnoughShares
+	}
+	size := len(shares[0])
+	xs := make([]*big.Int, len(shares))
+	seen := map[byte]bool{}
+	for i, share := range shares {
+		if len(share) == 0 || len(share) != size {
+			return nil, ErrInvalidShare
+		}
+		x := share[size-1]
+		if x == 0 {
+			return nil, ErrInvalidShare
+		}
+		if seen[x] {
+			return nil, ErrDuplicatedShare
+		}
+		seen[x] = true
+		xs[i] = big.NewInt(int64(x))
+	}
+	secret := make([]byte, size-1)
+	ys := make([]*big.Int, len(shares))
+	for j := range secret {
+		for i, share := range shares {
+			ys[i] = big.NewInt(int64(share[j]))
+		}
+		secret[j] = gf256Byte(interpolatePolynomial(GF256, xs, ys, big.NewInt(0)))
+	}
+	return secret, nil
+}
+
+// hideMessageGF256 generates the shares of the message with the GF(2^8)
+// backend and returns them encoded as hexadecimal strings, or as mnemonics if
+// the configuration uses the mnemonic encoding. It returns an error if the
+// configuration is not valid.
+func hideMessageGF256(message []byte, conf *Config) ([]string, error) {
+	// the configuration is prepared on a copy, so the caller's one is not
+	// modified
+	conf, err := messageConfig(message, conf)
+	if err != nil {
+		return nil, err
+	}
+	if err := conf.ValidConfig(message); err != nil {
+		return nil, err
+	}
+	shares, err := splitGF256(message, conf.Shares, conf.Min)
+	if err != nil {
+		return nil, err
+	}
+	strShares := make([]string, len(shares))
+	for i, share := range shares {
+		strShares[i] = encodeShareText(share, conf.Encoding)
+	}
+	return strShares, nil
+}
+
+// recoverMessageGF256 recovers the message from the shares provided as
+// hexadecimal strings or mnemonics with the GF(2^8) backend. If the minimum
+// number of shares of the configuration is defined, it is used to check that
+// there are enough shares, because the shares do not include the threshold.
+// If any share cannot be decoded, it returns a ShareError with the position of
+// the share in the input.
+func recoverMessageGF256(inputs []string, conf *Config) ([]byte, error) {
+	shares := make([][]byte, len(inputs))
+	for i, input := range inputs {
+		var err error
+		if shares[i], err = decodeShareText(input); err != nil {
+			return nil, &ShareError{Position: i, Err: err}
+		}
+	}
+	if len(shares) < conf.Min {
+		return nil, ErrNotEnoughShares
+	}
+	return combineGF256(shares)
+}
@@ -0,0 +1,97 @@
+package gosss
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func Test_gf256MulDiv(t *testing.T) {
+	// 0x53 * 0xca = 0x01 with the AES polynomial
+	if r := gf256Mul(0x53, 0xca); r != 0x01 {
+		t.Errorf("expected 1, got %d", r)
+	}
+	for a := 0; a < 256; a++ {
+		for b := 1; b < 256; b++ {
+			if r := gf256Div(gf256Mul(byte(a), byte(b)), byte(b)); r != byte(a) {
+				t.Fatalf("expected %d, got %d", a, r)
+			}
+		}
+	}
+}
+
+func Test_splitCombineGF256(t *testing.T) {
+	secret := []byte("gf256 secret")
+	shares, err := splitGF256(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, share := range shares {
+		if len(share) != len(secret)+1 {
+			t.Errorf("unexpected share length: %d", len(share))
+		}
+	}
+	recovered, err := combineGF256([][]byte{shares[4], shares[0], shares[2]})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		t.Errorf("expected %s, got %s", secret, recovered)
+	}
+	if _, err := combineGF256([][]byte{shares[0], shares[0]}); err != ErrDuplicatedShare {
+		t.Errorf("expected %v, got %v", ErrDuplicatedShare, err)
+	}
+	if _, err := combineGF256([][]byte{shares[0], shares[1][1:]}); err != ErrInvalidShare {
+		t.Errorf("expected %v, got %v", ErrInvalidShare, err)
+	}
+	if _, err := combineGF256(nil); err != ErrNotEnoughShares {
+		t.Errorf("expected %v, got %v", ErrNotEnoughShares, err)
+	}
+}
+
+func TestHideRecoverMessageGF256(t *testing.T) {
+	config := &Config{
+		Shares:  8,
+		Min:     5,
+		Backend: GF256Backend,
+	}
+	message := bytes.Repeat(examplePrivateMessage, 4)
+	shares, err := HideMessage(message, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the configuration is not modified
+	if config.Prime != nil || config.PrimeName != "" {
+		t.Errorf("unexpected prime: %v %s", config.Prime, config.PrimeName)
+	}
+	for _, share := range shares {
+		if b, _ := hex.DecodeString(share); len(b) != len(message)+1 {
+			t.Errorf("unexpected share length: %d", len(b))
+		}
+	}
+	recovered, err := RecoverMessage(shares[3:], config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(recovered, message) {
+		t.Errorf("expected %s, got %s", message, recovered)
+	}
+	if _, err := RecoverMessage(shares[:4], config); err != ErrNotEnoughShares {
+		t.Errorf("expected %v, got %v", ErrNotEnoughShares, err)
+	}
+	shares[1] = "zz"
+	if _, err := RecoverMessage(shares, config); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+	// typed shares are not supported by the backend
+	if _, err := HideMessageShares(message, config); err != ErrUnsupportedBackend {
+		t.Errorf("expected %v, got %v", ErrUnsupportedBackend, err)
+	}
+	// the x coordinate must fit in a byte
+	if _, err := HideMessage(message, &Config{Shares: 256, Min: 3, Backend: GF256Backend}); err != ErrConfigShares {
+		t.Errorf("expected %v, got %v", ErrConfigShares, err)
+	}
+	if _, err := HideMessage(message, &Config{Shares: 5, Min: 3, Backend: Backend(10)}); err != ErrConfigBackend {
+		t.Errorf("expected %v, got %v", ErrConfigBackend, err)
+	}
+}
@@ -1,3 +1,7 @@
 module github.com/lucasmenendez/gosss
 
 go 1.22.0
+
+require golang.org/x/term v0.22.0
+
+require golang.org/x/sys v0.22.0 // indirect
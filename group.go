@@ -0,0 +1,337 @@
+package gosss
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+)
+
+// Group interface defines a cyclic group of prime order where the discrete
+// logarithm problem is hard, used to commit to the coefficients of the
+// polynomials without revealing them. The elements of the group are handled
+// with their canonical binary encoding, so two elements are equal if their
+// encodings are equal. The methods follow the additive notation of elliptic
+// curves, for multiplicative groups Add is the product of the elements and
+// ScalarMul is the exponentiation of an element. The groups of the package are
+// implemented with math/big, whose operations are not constant time, so the
+// time to commit to the polynomials depends on the coefficients, which include
+// the message. They are meant for dealers that run on trusted machines, where
+// an attacker can not measure the time of the operations, and a constant time
+// implementation of the interface should be provided otherwise.
+type Group interface {
+	// Order returns the prime order of the group, which must be the prime
+	// number used as finite field to share the message.
+	Order() *big.Int
+	// ScalarBaseMul returns the generator of the group multiplied by the
+	// scalar provided.
+	ScalarBaseMul(k *big.Int) []byte
+	// ScalarMul returns the element provided multiplied by the scalar
+	// provided. It returns an error if the element is not valid.
+	ScalarMul(element []byte, k *big.Int) ([]byte, error)
+	// Add returns the result of the group operation of the elements
+	// provided. It returns an error if any element is not valid.
+	Add(a, b []byte) ([]byte, error)
+	// HashToElement returns an element of the group derived from the data
+	// provided, whose discrete logarithm with respect to the generator is
+	// unknown.
+	HashToElement(data []byte) []byte
+}
+
+// BN254G1 is the G1 group of the bn254 curve, y^2 = x^3 + 3, whose order is
+// the bn254 𝔽r prime, so it is the default group used to commit to the
+// polynomials generated with the default prime.
+var BN254G1 Group = newShortWeierstrass(
+	"21888242871839275222246405745257275088696311157297823662689037894645226208583",
+	"3", "1", "2", DefaultPrime.String())
+
+// shortWeierstrass struct represents the group of points of a short
+// Weierstrass elliptic curve in the form y^2 = x^3 + b over the finite field
+// defined by the prime p, with a generator point (gx, gy) of prime order n.
+// The points are encoded as the concatenation of their coordinates with a
+// fixed size, and the point at infinity is encoded as zeros, because (0, 0)
+// is not a point of the curve. Every point of the curve is considered part of
+// the group, so only curves with cofactor 1 are supported.
+type shortWeierstrass struct {
+	p, b, gx, gy, n *big.Int
+	size            int
+}
+
+// newShortWeierstrass creates a new short Weierstrass curve with the
+// parameters provided as decimal strings. It panics if any parameter is not
+// valid, so it must only be used to define known curves.
+func newShortWeierstrass(p, b, gx, gy, n string) *shortWeierstrass {
+	c := &shortWeierstrass{}
+	for _, param := range []struct {
+		dst **big.Int
+		src string
+	}{{&c.p, p}, {&c.b, b}, {&c.gx, gx}, {&c.gy, gy}, {&c.n, n}} {
+		var ok bool
+		if *param.dst, ok = new(big.Int).SetString(param.src, 10); !ok {
+			panic("invalid curve parameter")
+		}
+	}
+	c.size = len(c.p.Bytes())
+	return c
+}
+
+// Order returns the order of the generator of the curve.
+func (c *shortWeierstrass) Order() *big.Int {
+	return new(big.Int).Set(c.n)
+}
+
+// ScalarBaseMul returns the generator of the curve multiplied by k.
+func (c *shortWeierstrass) ScalarBaseMul(k *big.Int) []byte {
+	x, y := c.scalarMul(c.gx, c.gy, k)
+	return c.encode(x, y)
+}
+
+// ScalarMul returns the point provided multiplied by k. It returns an error if
+// the point is not a valid point of the curve.
+func (c *shortWeierstrass) ScalarMul(element []byte, k *big.Int) ([]byte, error) {
+	x, y, err := c.decode(element)
+	if err != nil {
+		return nil, err
+	}
+	rx, ry := c.scalarMul(x, y, k)
+	return c.encode(rx, ry), nil
+}
+
+// Add returns the sum of the points provided. It returns an error if any
+// point is not a valid point of the curve.
+func (c *shortWeierstrass) Add(a, b []byte) ([]byte, error) {
+	ax, ay, err := c.decode(a)
+	if err != nil {
+		return nil, err
+	}
+	bx, by, err := c.decode(b)
+	if err != nil {
+		return nil, err
+	}
+	x, y := c.add(ax, ay, bx, by)
+	return c.encode(x, y), nil
+}
+
+// HashToElement returns a point of the curve derived from the data provided
+// using the try and increment method: the x coordinate is the hash of the
+// data and a counter, which is incremented until x^3 + b has a square root.
+func (c *shortWeierstrass) HashToElement(data []byte) []byte {
+	rhs := new(big.Int)
+	for counter := uint32(0); ; counter++ {
+		x := new(big.Int).SetBytes(hashWithCounter(data, counter))
+		x.Mod(x, c.p)
+		rhs.Exp(x, big.NewInt(3), c.p)
+		rhs.Add(rhs, c.b)
+		rhs.Mod(rhs, c.p)
+		if y := new(big.Int).ModSqrt(rhs, c.p); y != nil {
+			return c.encode(x, y)
+		}
+	}
+}
+
+// encode returns the fixed size encoding of the point provided, a nil x
+// coordinate represents the point at infinity.
+func (c *shortWeierstrass) encode(x, y *big.Int) []byte {
+	b := make([]byte, 2*c.size)
+	if x != nil {
+		x.FillBytes(b[:c.size])
+		y.FillBytes(b[c.size:])
+	}
+	return b
+}
+
+// decode returns the coordinates of the point encoded, or nil coordinates for
+// the point at infinity. It returns an error if the encoding has a wrong size
+// or if the point is not on the curve.
+func (c *shortWeierstrass) decode(b []byte) (*big.Int, *big.Int, error) {
+	if len(b) != 2*c.size {
+		return nil, nil, ErrInvalidGroupElement
+	}
+	x := new(big.Int).SetBytes(b[:c.size])
+	y := new(big.Int).SetBytes(b[c.size:])
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, nil, nil
+	}
+	if x.Cmp(c.p) >= 0 || y.Cmp(c.p) >= 0 {
+		return nil, nil, ErrInvalidGroupElement
+	}
+	// check that y^2 = x^3 + b
+	lhs := new(big.Int).Mul(y, y)
+	lhs.Mod(lhs, c.p)
+	rhs := new(big.Int).Exp(x, big.NewInt(3), c.p)
+	rhs.Add(rhs, c.b)
+	rhs.Mod(rhs, c.p)
+	if lhs.Cmp(rhs) != 0 {
+		return nil, nil, ErrInvalidGroupElement
+	}
+	return x, y, nil
+}
+
+// add returns the sum of the points provided in affine coordinates, nil
+// coordinates represent the point at infinity.
+func (c *shortWeierstrass) add(ax, ay, bx, by *big.Int) (*big.Int, *big.Int) {
+	if ax == nil {
+		return bx, by
+	}
+	if bx == nil {
+		return ax, ay
+	}
+	// calculate the slope of the line that crosses both points, or the slope
+	// of the tangent if they are the same point
+	slope := new(big.Int)
+	if ax.Cmp(bx) == 0 {
+		// the points are opposite or the point has y = 0, so the result is
+		// the point at infinity
+		if ay.Cmp(by) != 0 || ay.Sign() == 0 {
+			return nil, nil
+		}
+		// slope = 3x^2 / 2y
+		num := new(big.Int).Mul(ax, ax)
+		num.Mul(num, big.NewInt(3))
+		den := new(big.Int).Lsh(ay, 1)
+		den.ModInverse(den, c.p)
+		slope.Mul(num, den)
+	} else {
+		// slope = (by - ay) / (bx - ax)
+		num := new(big.Int).Sub(by, ay)
+		den := new(big.Int).Sub(bx, ax)
+		den.Mod(den, c.p)
+		den.ModInverse(den, c.p)
+		slope.Mul(num, den)
+	}
+	slope.Mod(slope, c.p)
+	// x = slope^2 - ax - bx, y = slope * (ax - x) - ay
+	x := new(big.Int).Mul(slope, slope)
+	x.Sub(x, ax)
+	x.Sub(x, bx)
+	x.Mod(x, c.p)
+	y := new(big.Int).Sub(ax, x)
+	y.Mul(y, slope)
+	y.Sub(y, ay)
+	y.Mod(y, c.p)
+	return x, y
+}
+
+// scalarMul returns the point provided multiplied by k using the double and
+// add method. The scalar is reduced modulo the order of the curve first. The
+// number of operations depends on the bits of the scalar, so it is not
+// constant time, see Group.
+func (c *shortWeierstrass) scalarMul(x, y, k *big.Int) (*big.Int, *big.Int) {
+	scalar := new(big.Int).Mod(k, c.n)
+	var rx, ry *big.Int
+	for i := scalar.BitLen() - 1; i >= 0; i-- {
+		rx, ry = c.add(rx, ry, rx, ry)
+		if scalar.Bit(i) == 1 {
+			rx, ry = c.add(rx, ry, x, y)
+		}
+	}
+	return rx, ry
+}
+
+// schnorrGroup struct represents the subgroup of prime order q of the
+// multiplicative group of integers modulo the prime p, generated by g. The
+// elements are encoded with the fixed size of the prime p.
+type schnorrGroup struct {
+	p, q, g *big.Int
+	size    int
+}
+
+// NewSchnorrGroup creates a new Schnorr group with the prime modulus p, the
+// prime order q and the generator g provided. It returns an error if p or q
+// are not prime numbers, if q does not divide p - 1, or if g does not
+// generate a subgroup of order q. The order q must be the prime number used
+// as finite field to share the message.
+func NewSchnorrGroup(p, q, g *big.Int) (Group, error) {
+	if p == nil || q == nil || g == nil || !p.ProbablyPrime(20) || !q.ProbablyPrime(20) {
+		return nil, ErrInvalidGroup
+	}
+	pMinusOne := new(big.Int).Sub(p, big.NewInt(1))
+	if new(big.Int).Mod(pMinusOne, q).Sign() != 0 {
+		return nil, ErrInvalidGroup
+	}
+	if g.Cmp(big.NewInt(1)) <= 0 || g.Cmp(p) >= 0 || new(big.Int).Exp(g, q, p).Cmp(big.NewInt(1)) != 0 {
+		return nil, ErrInvalidGroup
+	}
+	return &schnorrGroup{
+		p:    new(big.Int).Set(p),
+		q:    new(big.Int).Set(q),
+		g:    new(big.Int).Set(g),
+		size: len(p.Bytes()),
+	}, nil
+}
+
+// Order returns the order of the subgroup.
+func (g *schnorrGroup) Order() *big.Int {
+	return new(big.Int).Set(g.q)
+}
+
+// ScalarBaseMul returns the generator of the subgroup raised to k.
+func (g *schnorrGroup) ScalarBaseMul(k *big.Int) []byte {
+	return g.encode(new(big.Int).Exp(g.g, new(big.Int).Mod(k, g.q), g.p))
+}
+
+// ScalarMul returns the element provided raised to k. It returns an error if
+// the element is not a valid element of the subgroup.
+func (g *schnorrGroup) ScalarMul(element []byte, k *big.Int) ([]byte, error) {
+	e, err := g.decode(element)
+	if err != nil {
+		return nil, err
+	}
+	return g.encode(e.Exp(e, new(big.Int).Mod(k, g.q), g.p)), nil
+}
+
+// Add returns the product of the elements provided. It returns an error if
+// any element is not a valid element of the subgroup.
+func (g *schnorrGroup) Add(a, b []byte) ([]byte, error) {
+	ea, err := g.decode(a)
+	if err != nil {
+		return nil, err
+	}
+	eb, err := g.decode(b)
+	if err != nil {
+		return nil, err
+	}
+	ea.Mul(ea, eb)
+	return g.encode(ea.Mod(ea, g.p)), nil
+}
+
+// HashToElement returns an element of the subgroup derived from the data
+// provided, raising the hash of the data and a counter to (p - 1) / q, which
+// is incremented until the result is not the identity.
+func (g *schnorrGroup) HashToElement(data []byte) []byte {
+	cofactor := new(big.Int).Sub(g.p, big.NewInt(1))
+	cofactor.Div(cofactor, g.q)
+	for counter := uint32(0); ; counter++ {
+		e := new(big.Int).SetBytes(hashWithCounter(data, counter))
+		e.Exp(e.Mod(e, g.p), cofactor, g.p)
+		if e.Cmp(big.NewInt(1)) > 0 {
+			return g.encode(e)
+		}
+	}
+}
+
+// encode returns the fixed size encoding of the element provided.
+func (g *schnorrGroup) encode(e *big.Int) []byte {
+	return e.FillBytes(make([]byte, g.size))
+}
+
+// decode returns the element encoded. It returns an error if the encoding has
+// a wrong size or if the element does not belong to the subgroup.
+func (g *schnorrGroup) decode(b []byte) (*big.Int, error) {
+	if len(b) != g.size {
+		return nil, ErrInvalidGroupElement
+	}
+	e := new(big.Int).SetBytes(b)
+	if e.Sign() == 0 || e.Cmp(g.p) >= 0 || new(big.Int).Exp(e, g.q, g.p).Cmp(big.NewInt(1)) != 0 {
+		return nil, ErrInvalidGroupElement
+	}
+	return e, nil
+}
+
+// hashWithCounter returns the sha256 hash of the data provided followed by the
+// counter encoded in big endian.
+func hashWithCounter(data []byte, counter uint32) []byte {
+	hash := sha256.New()
+	hash.Write(data)
+	hash.Write(binary.BigEndian.AppendUint32(nil, counter))
+	return hash.Sum(nil)
+}
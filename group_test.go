@@ -0,0 +1,110 @@
+package gosss
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestBN254G1(t *testing.T) {
+	// the generator multiplied by the order is the point at infinity
+	infinity := BN254G1.ScalarBaseMul(big.NewInt(0))
+	if !bytes.Equal(BN254G1.ScalarBaseMul(BN254G1.Order()), infinity) {
+		t.Errorf("expected point at infinity")
+	}
+	// g*a + g*b = g*(a+b) and (g*a)*b = g*(a*b)
+	a, b := big.NewInt(123456789), big.NewInt(987654321)
+	ga, gb := BN254G1.ScalarBaseMul(a), BN254G1.ScalarBaseMul(b)
+	sum, err := BN254G1.Add(ga, gb)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(sum, BN254G1.ScalarBaseMul(new(big.Int).Add(a, b))) {
+		t.Errorf("unexpected sum")
+	}
+	prod, err := BN254G1.ScalarMul(ga, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(prod, BN254G1.ScalarBaseMul(new(big.Int).Mul(a, b))) {
+		t.Errorf("unexpected product")
+	}
+	// doubling and adding the point at infinity
+	double, err := BN254G1.Add(ga, ga)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(double, BN254G1.ScalarBaseMul(big.NewInt(2*123456789))) {
+		t.Errorf("unexpected double")
+	}
+	if res, err := BN254G1.Add(ga, infinity); err != nil || !bytes.Equal(res, ga) {
+		t.Errorf("unexpected sum with infinity: %v", err)
+	}
+	// invalid points
+	invalid := bytes.Clone(ga)
+	invalid[len(invalid)-1] ^= 0x01
+	if _, err := BN254G1.Add(ga, invalid); err != ErrInvalidGroupElement {
+		t.Errorf("expected %v, got %v", ErrInvalidGroupElement, err)
+	}
+	if _, err := BN254G1.ScalarMul(ga[1:], a); err != ErrInvalidGroupElement {
+		t.Errorf("expected %v, got %v", ErrInvalidGroupElement, err)
+	}
+}
+
+func TestNewSchnorrGroup(t *testing.T) {
+	// p = 2q + 1, g = 2^2 mod p
+	p, q, g := big.NewInt(2039), big.NewInt(1019), big.NewInt(4)
+	group, err := NewSchnorrGroup(p, q, g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if group.Order().Cmp(q) != 0 {
+		t.Errorf("unexpected order: %v", group.Order())
+	}
+	a, b := big.NewInt(5), big.NewInt(7)
+	sum, err := group.Add(group.ScalarBaseMul(a), group.ScalarBaseMul(b))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(sum, group.ScalarBaseMul(big.NewInt(12))) {
+		t.Errorf("unexpected product")
+	}
+	// element out of the subgroup
+	if _, err := group.ScalarMul(big.NewInt(2038).FillBytes(make([]byte, 2)), a); err != ErrInvalidGroupElement {
+		t.Errorf("expected %v, got %v", ErrInvalidGroupElement, err)
+	}
+	// invalid parameters
+	if _, err := NewSchnorrGroup(big.NewInt(2040), q, g); err != ErrInvalidGroup {
+		t.Errorf("expected %v, got %v", ErrInvalidGroup, err)
+	}
+	if _, err := NewSchnorrGroup(p, big.NewInt(1013), g); err != ErrInvalidGroup {
+		t.Errorf("expected %v, got %v", ErrInvalidGroup, err)
+	}
+	if _, err := NewSchnorrGroup(p, q, big.NewInt(2038)); err != ErrInvalidGroup {
+		t.Errorf("expected %v, got %v", ErrInvalidGroup, err)
+	}
+}
+
+func TestHashToElement(t *testing.T) {
+	schnorr, err := NewSchnorrGroup(big.NewInt(2039), big.NewInt(1019), big.NewInt(4))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, group := range []Group{BN254G1, schnorr} {
+		h := group.HashToElement([]byte("test"))
+		if !bytes.Equal(h, group.HashToElement([]byte("test"))) {
+			t.Errorf("expected deterministic element")
+		}
+		if bytes.Equal(h, group.HashToElement([]byte("other"))) {
+			t.Errorf("expected different elements")
+		}
+		// the element is valid and it is not the identity
+		res, err := group.ScalarMul(h, big.NewInt(1))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if bytes.Equal(res, group.ScalarBaseMul(big.NewInt(0))) {
+			t.Errorf("unexpected identity element")
+		}
+	}
+}
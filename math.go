@@ -29,16 +29,25 @@ func randBigInt(n int, base *big.Int) (*big.Int, error) {
 	return new(big.Int).Mod(randomBigInt, base), nil
 }
 
+// randFieldElement generates a random element of the finite field defined by
+// the prime provided. It reads more bytes than the size of the prime to make
+// the bias of the modular reduction negligible, so every element of the field
+// can be generated. It returns an error if the random number cannot be
+// generated.
+func randFieldElement(prime *big.Int) (*big.Int, error) {
+	return randBigInt(len(prime.Bytes())+16, prime)
+}
+
 // calcCoeffs function generates the coefficients for the polynomial. It takes
 // the secret and the number of coefficients to generate. It returns the
 // coefficients as a list of big.Int. It returns an error if the coefficients
 // cannot be generated. The secret is the first coefficient of the polynomial,
-// the rest of the coefficients are random numbers.
+// the rest of the coefficients are random elements of the finite field.
 func calcCoeffs(secret, prime *big.Int, k int) ([]*big.Int, error) {
 	// calculate k-1 random coefficients
 	randCoeffs := make([]*big.Int, k-1)
 	for i := 0; i < len(randCoeffs); i++ {
-		randCoeff, err := randBigInt(8, prime)
+		randCoeff, err := randFieldElement(prime)
 		if err != nil {
 			return nil, err
 		}
@@ -53,15 +62,9 @@ func calcCoeffs(secret, prime *big.Int, k int) ([]*big.Int, error) {
 // f(x) = a0 + a1*x + a2*x^2 + ... + an*x^n
 // where a0, a1, ..., an are the coefficients.
 // It uses the Horner's method to avoid the calculation of the powers of x.
-// It uses the prime number defined in the package as finite field.
+// It uses the prime number provided as finite field.
 func solvePolynomial(coeffs []*big.Int, x, prime *big.Int) *big.Int {
-	accum := big.NewInt(0)
-	for i := len(coeffs) - 1; i >= 0; i-- {
-		accum.Mul(accum, x)
-		accum.Add(accum, coeffs[i])
-		accum.Mod(accum, prime)
-	}
-	return accum
+	return evalPolynomial(&PrimeField{prime: prime}, coeffs, x)
 }
 
 // calcShares function calculates the shares of the polynomial for the given
@@ -93,49 +96,112 @@ func calcShares(coeffs []*big.Int, nshares int, prime *big.Int) ([]*big.Int, []*
 // where the operations are performed modulo a prime number to ensure results
 // remain within the finite field.
 func lagrangeInterpolation(xCoords, yCoords []*big.Int, prime, _x *big.Int) *big.Int {
-	// initialize result
-	result := big.NewInt(0)
-	// temporary variables used inside the loop to avoid reallocating them, it
-	// will be overwritten in each iteration with some values that are not used
-	// in the next iteration
-	temp1 := big.NewInt(0)
-	temp2 := big.NewInt(0)
-	// numerator and denominator for each point to be calculated in the loop
-	numerator := big.NewInt(1)
-	denominator := big.NewInt(1)
-	diff := big.NewInt(0)
-	// iterate over all the points
-	for i := range xCoords {
-		// reset the numerator and denominator for each point
-		numerator.SetInt64(1)
-		denominator.SetInt64(1)
-		for j := range xCoords {
-			// skip if i == j
-			if i == j {
+	return interpolatePolynomial(&PrimeField{prime: prime}, xCoords, yCoords, _x)
+}
+
+// solveLinearSystem solves the system of linear equations defined by the
+// augmented matrix provided, where every row is an equation with the
+// coefficients of the unknowns followed by the constant term, in the finite
+// field defined by the prime. It uses the Gauss-Jordan elimination and, if the
+// system has more than one solution, it returns the one with the free
+// unknowns set to zero. It returns false if the system has no solution. The
+// matrix provided is modified during the elimination.
+func solveLinearSystem(matrix [][]*big.Int, prime *big.Int) ([]*big.Int, bool) {
+	nunknowns := len(matrix[0]) - 1
+	pivots := make([]int, 0, nunknowns)
+	row := 0
+	for col := 0; col < nunknowns && row < len(matrix); col++ {
+		// find a row with a non zero coefficient for the current unknown and
+		// move it to the current row
+		pivot := -1
+		for i := row; i < len(matrix); i++ {
+			if matrix[i][col].Sign() != 0 {
+				pivot = i
+				break
+			}
+		}
+		if pivot < 0 {
+			continue
+		}
+		matrix[row], matrix[pivot] = matrix[pivot], matrix[row]
+		// normalize the pivot row to get a coefficient of one
+		inv := new(big.Int).ModInverse(matrix[row][col], prime)
+		for j := col; j <= nunknowns; j++ {
+			matrix[row][j].Mul(matrix[row][j], inv)
+			matrix[row][j].Mod(matrix[row][j], prime)
+		}
+		// eliminate the unknown from the rest of the rows
+		temp := new(big.Int)
+		for i := range matrix {
+			if i == row || matrix[i][col].Sign() == 0 {
 				continue
 			}
-			// calculate numerator: (x - x_m)
-			temp1.Sub(_x, xCoords[j])
-			numerator.Mul(numerator, temp1)
-			// modular operation
-			numerator.Mod(numerator, prime)
-			// calculate denominator: (x_j - x_m)
-			diff.Sub(xCoords[i], xCoords[j])
-			// modular inverse
-			temp2.ModInverse(diff, prime)
-			denominator.Mul(denominator, temp2)
-			// modular operation
-			denominator.Mod(denominator, prime)
+			factor := new(big.Int).Set(matrix[i][col])
+			for j := col; j <= nunknowns; j++ {
+				temp.Mul(factor, matrix[row][j])
+				matrix[i][j].Sub(matrix[i][j], temp)
+				matrix[i][j].Mod(matrix[i][j], prime)
+			}
+		}
+		pivots = append(pivots, col)
+		row++
+	}
+	// the system has no solution if any remaining equation has no unknowns
+	// but a non zero constant term
+	for i := row; i < len(matrix); i++ {
+		if matrix[i][nunknowns].Sign() != 0 {
+			return nil, false
 		}
-		// combine the fraction with y_i and add it to the result
-		temp1.Mul(yCoords[i], numerator) // y_i * numerator
-		temp1.Mod(temp1, prime)
-		temp2.Mul(temp1, denominator) // y_i * numerator / denominator
-		temp2.Mod(temp2, prime)
-		// add the result of the fraction to the final result
-		result.Add(result, temp2)
-		// ensure the result is within the field
-		result.Mod(result, prime)
 	}
-	return result
+	solution := make([]*big.Int, nunknowns)
+	for i := range solution {
+		solution[i] = big.NewInt(0)
+	}
+	for i, col := range pivots {
+		solution[col].Set(matrix[i][nunknowns])
+	}
+	return solution, true
+}
+
+// dividePolynomials divides the polynomial num by the polynomial den in the
+// finite field defined by the prime, both defined by their coefficients from
+// the lowest to the highest degree. It returns the coefficients of the
+// quotient and the remainder. The leading coefficient of den must not be
+// zero.
+func dividePolynomials(num, den []*big.Int, prime *big.Int) ([]*big.Int, []*big.Int) {
+	remainder := make([]*big.Int, len(num))
+	for i, coeff := range num {
+		remainder[i] = new(big.Int).Mod(coeff, prime)
+	}
+	if len(num) < len(den) {
+		return []*big.Int{big.NewInt(0)}, remainder
+	}
+	quotient := make([]*big.Int, len(num)-len(den)+1)
+	inv := new(big.Int).ModInverse(den[len(den)-1], prime)
+	temp := new(big.Int)
+	for i := len(quotient) - 1; i >= 0; i-- {
+		// the coefficient of the quotient cancels the highest degree of the
+		// current remainder
+		coeff := new(big.Int).Mul(remainder[i+len(den)-1], inv)
+		coeff.Mod(coeff, prime)
+		quotient[i] = coeff
+		for j, d := range den {
+			temp.Mul(coeff, d)
+			remainder[i+j].Sub(remainder[i+j], temp)
+			remainder[i+j].Mod(remainder[i+j], prime)
+		}
+	}
+	return quotient, remainder[:len(den)-1]
+}
+
+// lagrangeBasis calculates the Lagrange basis polynomial of the point i of the
+// x coordinates provided, evaluated at a specific x value, in the finite
+// field defined by the prime:
+//
+//	l_i(x) = product((x - x_m) / (x_i - x_m) for m != i)
+//
+// Multiplying it by the y coordinate of the point i results in its
+// contribution to the Lagrange interpolation at x.
+func lagrangeBasis(xCoords []*big.Int, i int, prime, _x *big.Int) *big.Int {
+	return fieldLagrangeBasis(&PrimeField{prime: prime}, xCoords, i, _x)
 }
@@ -190,3 +190,72 @@ func Test_lagrangeInterpolation(t *testing.T) {
 		t.Errorf("x = 4 failed, expected %v, got %v", y4, result4)
 	}
 }
+
+func Test_solveLinearSystem(t *testing.T) {
+	prime := big.NewInt(17)
+	// x + 2y = 5, 3x + y = 5 (mod 17) => x = 1, y = 2
+	matrix := [][]*big.Int{
+		{big.NewInt(1), big.NewInt(2), big.NewInt(5)},
+		{big.NewInt(3), big.NewInt(1), big.NewInt(5)},
+	}
+	solution, ok := solveLinearSystem(matrix, prime)
+	if !ok {
+		t.Fatalf("expected solution")
+	}
+	if solution[0].Int64() != 1 || solution[1].Int64() != 2 {
+		t.Errorf("unexpected solution: %v", solution)
+	}
+	// x + y = 1, 2x + 2y = 3 (mod 17) has no solution
+	matrix = [][]*big.Int{
+		{big.NewInt(1), big.NewInt(1), big.NewInt(1)},
+		{big.NewInt(2), big.NewInt(2), big.NewInt(3)},
+	}
+	if _, ok := solveLinearSystem(matrix, prime); ok {
+		t.Errorf("expected no solution")
+	}
+}
+
+func Test_dividePolynomials(t *testing.T) {
+	prime := big.NewInt(17)
+	// (x^2 + 3x + 2) / (x + 1) = x + 2
+	num := []*big.Int{big.NewInt(2), big.NewInt(3), big.NewInt(1)}
+	den := []*big.Int{big.NewInt(1), big.NewInt(1)}
+	quotient, remainder := dividePolynomials(num, den, prime)
+	if len(quotient) != 2 || quotient[0].Int64() != 2 || quotient[1].Int64() != 1 {
+		t.Errorf("unexpected quotient: %v", quotient)
+	}
+	if len(remainder) != 1 || remainder[0].Sign() != 0 {
+		t.Errorf("unexpected remainder: %v", remainder)
+	}
+	// (x^2 + 3) / (x + 1) = x - 1, remainder 4
+	num = []*big.Int{big.NewInt(3), big.NewInt(0), big.NewInt(1)}
+	quotient, remainder = dividePolynomials(num, den, prime)
+	if quotient[0].Int64() != 16 || quotient[1].Int64() != 1 || remainder[0].Int64() != 4 {
+		t.Errorf("unexpected result: %v %v", quotient, remainder)
+	}
+}
+
+func Test_lagrangeBasis(t *testing.T) {
+	prime := big.NewInt(17)
+	// f(x) = (6 + x + 2x^2 + 3x^3) % 17
+	coeffs := []*big.Int{
+		big.NewInt(6),
+		big.NewInt(1),
+		big.NewInt(2),
+		big.NewInt(3),
+	}
+	xs, ys := calcShares(coeffs, len(coeffs), prime)
+	// the sum of the contributions of every point is the interpolation
+	for _, x := range []*big.Int{big.NewInt(0), big.NewInt(7)} {
+		sum := big.NewInt(0)
+		for i := range xs {
+			contribution := new(big.Int).Mul(ys[i], lagrangeBasis(xs, i, prime, x))
+			sum.Add(sum, contribution)
+		}
+		sum.Mod(sum, prime)
+		expected := lagrangeInterpolation(xs, ys, prime, x)
+		if sum.Cmp(expected) != 0 {
+			t.Errorf("x = %v failed, expected %v, got %v", x, expected, sum)
+		}
+	}
+}
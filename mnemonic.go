@@ -0,0 +1,143 @@
+package gosss
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	// mnemonicWordBits is the number of bits encoded by every word of a
+	// mnemonic, which is the base 2 logarithm of the size of the word list.
+	mnemonicWordBits = 11
+	// mnemonicMinChecksumBits is the minimum number of checksum bits of a
+	// mnemonic, the rest of the bits of its last word are also checksum bits.
+	mnemonicMinChecksumBits = 8
+)
+
+// wordlistEnglish is the english word list of BIP39, with 2048 words.
+//
+//go:embed wordlist_english.txt
+var wordlistEnglish string
+
+var (
+	// mnemonicWords contains the words of the mnemonics, every word encodes
+	// its position in the list.
+	mnemonicWords = strings.Fields(wordlistEnglish)
+	// mnemonicIndexes maps every word of the mnemonics to its position in
+	// the list.
+	mnemonicIndexes = func() map[string]int {
+		indexes := make(map[string]int, len(mnemonicWords))
+		for i, word := range mnemonicWords {
+			indexes[word] = i
+		}
+		return indexes
+	}()
+)
+
+// EncodeMnemonic encodes the data provided as a sequence of words of the
+// BIP39 english word list, separated by spaces, so it can be written on paper
+// or read aloud. Like BIP39, every word encodes 11 bits and the data is
+// followed by the first bits of its SHA-256 hash as checksum, but the data is
+// prefixed by its length encoded as a varint, so it can have any length. The
+// checksum has at least 8 bits and fills the last word.
+func EncodeMnemonic(data []byte) string {
+	payload := binary.AppendUvarint(nil, uint64(len(data)))
+	payload = append(payload, data...)
+	checksum := sha256.Sum256(payload)
+	nWords := (8*len(payload) + mnemonicMinChecksumBits + mnemonicWordBits - 1) / mnemonicWordBits
+	bits := append(payload, checksum[:]...)
+	words := make([]string, nWords)
+	for i := range words {
+		words[i] = mnemonicWords[readBits(bits, i*mnemonicWordBits, mnemonicWordBits)]
+	}
+	return strings.Join(words, " ")
+}
+
+// DecodeMnemonic decodes the data from a mnemonic generated with
+// EncodeMnemonic. The words can be separated by any whitespace and are case
+// insensitive. It returns an error if any word is not in the word list, if
+// the number of words does not match the length of the data or if the
+// checksum does not match.
+func DecodeMnemonic(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	bits := make([]byte, (len(words)*mnemonicWordBits+7)/8)
+	for i, word := range words {
+		index, ok := mnemonicIndexes[strings.ToLower(word)]
+		if !ok {
+			return nil, ErrInvalidMnemonic
+		}
+		writeBits(bits, i*mnemonicWordBits, mnemonicWordBits, index)
+	}
+	length, prefixLen := binary.Uvarint(bits)
+	if prefixLen <= 0 || length > uint64(len(bits)) {
+		return nil, ErrInvalidMnemonic
+	}
+	// the checksum must fill the last word, so the number of words matches
+	// the length of the data
+	payloadLen := prefixLen + int(length)
+	checksumBits := len(words)*mnemonicWordBits - 8*payloadLen
+	if checksumBits < mnemonicMinChecksumBits || checksumBits >= mnemonicMinChecksumBits+mnemonicWordBits {
+		return nil, ErrInvalidMnemonic
+	}
+	checksum := sha256.Sum256(bits[:payloadLen])
+	for i := 0; i < checksumBits; i++ {
+		if readBits(bits, 8*payloadLen+i, 1) != readBits(checksum[:], i, 1) {
+			return nil, ErrMnemonicChecksum
+		}
+	}
+	return append([]byte{}, bits[prefixLen:payloadLen]...), nil
+}
+
+// isMnemonic returns true if the text provided is a mnemonic, which has more
+// than one word, instead of a hexadecimal string.
+func isMnemonic(text string) bool {
+	return len(strings.Fields(text)) > 1
+}
+
+// encodeShareText returns the text representation of the binary encoding of a
+// share with the encoding provided.
+func encodeShareText(b []byte, encoding Encoding) string {
+	if encoding == MnemonicEncoding {
+		return EncodeMnemonic(b)
+	}
+	return hex.EncodeToString(b)
+}
+
+// decodeShareText returns the binary encoding of a share from its text
+// representation, which can be a mnemonic or a hexadecimal string. It returns
+// an error if it cannot be decoded.
+func decodeShareText(text string) ([]byte, error) {
+	if isMnemonic(text) {
+		return DecodeMnemonic(text)
+	}
+	b, err := hex.DecodeString(strings.TrimSpace(text))
+	if err != nil {
+		return nil, ErrInvalidShare
+	}
+	return b, nil
+}
+
+// readBits returns the n bits of the data provided starting at the bit
+// offset provided, most significant bit first.
+func readBits(data []byte, offset, n int) int {
+	value := 0
+	for i := offset; i < offset+n; i++ {
+		value = value<<1 | int(data[i/8]>>(7-i%8)&1)
+	}
+	return value
+}
+
+// writeBits writes the n least significant bits of the value provided to the
+// data provided starting at the bit offset provided, most significant bit
+// first.
+func writeBits(data []byte, offset, n, value int) {
+	for i := 0; i < n; i++ {
+		if value>>(n-1-i)&1 == 1 {
+			pos := offset + i
+			data[pos/8] |= 1 << (7 - pos%8)
+		}
+	}
+}
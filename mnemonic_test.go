@@ -0,0 +1,118 @@
+package gosss
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEncodeDecodeMnemonic(t *testing.T) {
+	if len(mnemonicWords) != 1<<mnemonicWordBits || len(mnemonicIndexes) != len(mnemonicWords) {
+		t.Fatalf("unexpected word list length: %d", len(mnemonicWords))
+	}
+	for length := 0; length < 300; length++ {
+		data := bytes.Repeat([]byte{byte(length)}, length)
+		mnemonic := EncodeMnemonic(data)
+		decoded, err := DecodeMnemonic(mnemonic)
+		if err != nil {
+			t.Fatalf("unexpected error for length %d: %v", length, err)
+		}
+		if !bytes.Equal(decoded, data) {
+			t.Fatalf("expected %x, got %x", data, decoded)
+		}
+	}
+	data := []byte("mnemonic data")
+	words := strings.Fields(EncodeMnemonic(data))
+	// the words are case insensitive and can be separated by any whitespace
+	decoded, err := DecodeMnemonic(strings.ToUpper(strings.Join(words, "\n\t ")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(decoded, data) {
+		t.Errorf("expected %s, got %s", data, decoded)
+	}
+	// unknown words
+	wrong := append([]string{"notaword"}, words[1:]...)
+	if _, err := DecodeMnemonic(strings.Join(wrong, " ")); err != ErrInvalidMnemonic {
+		t.Errorf("expected %v, got %v", ErrInvalidMnemonic, err)
+	}
+	// missing or extra words
+	if _, err := DecodeMnemonic(strings.Join(words[:len(words)-1], " ")); err != ErrInvalidMnemonic {
+		t.Errorf("expected %v, got %v", ErrInvalidMnemonic, err)
+	}
+	extra := append(append([]string{}, words...), "abandon")
+	if _, err := DecodeMnemonic(strings.Join(extra, " ")); err != ErrInvalidMnemonic {
+		t.Errorf("expected %v, got %v", ErrInvalidMnemonic, err)
+	}
+	// swapped word in the data
+	swapped := append([]string{}, words...)
+	swapped[3] = mnemonicWords[(mnemonicIndexes[swapped[3]]+1)%len(mnemonicWords)]
+	if _, err := DecodeMnemonic(strings.Join(swapped, " ")); err != ErrMnemonicChecksum {
+		t.Errorf("expected %v, got %v", ErrMnemonicChecksum, err)
+	}
+}
+
+func TestHideRecoverMnemonic(t *testing.T) {
+	message := []byte("message hidden in words")
+	shares, err := HideMessage(message, &Config{Shares: 5, Min: 3, Encoding: MnemonicEncoding})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !isMnemonic(shares[0]) {
+		t.Fatalf("expected mnemonic, got %s", shares[0])
+	}
+	recovered, err := RecoverMessage([]string{shares[4], shares[1], shares[2]}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(recovered, message) {
+		t.Errorf("expected %s, got %s", message, recovered)
+	}
+	// mnemonics and hexadecimal shares can be mixed
+	var share Share
+	if err := share.UnmarshalText([]byte(shares[0])); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	recovered, err = RecoverMessage([]string{share.String(), shares[1], shares[2]}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(recovered, message) {
+		t.Errorf("expected %s, got %s", message, recovered)
+	}
+	mnemonic, err := share.Mnemonic()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mnemonic != shares[0] {
+		t.Errorf("expected %s, got %s", shares[0], mnemonic)
+	}
+	// a wrong word is detected with its position, by the checksum of the
+	// mnemonic or, rarely, by the checksum of the share
+	words := strings.Fields(shares[1])
+	words[len(words)/2] = mnemonicWords[(mnemonicIndexes[words[len(words)/2]]+7)%len(mnemonicWords)]
+	_, err = RecoverMessage([]string{shares[0], strings.Join(words, " "), shares[2]}, nil)
+	var shareErr *ShareError
+	if !errors.As(err, &shareErr) || shareErr.Position != 1 ||
+		(!errors.Is(err, ErrMnemonicChecksum) && !errors.Is(err, ErrShareChecksum)) {
+		t.Errorf("expected %v at position 1, got %v", ErrMnemonicChecksum, err)
+	}
+	// gf256 backend
+	conf := &Config{Shares: 4, Min: 2, Backend: GF256Backend, Encoding: MnemonicEncoding}
+	shares, err = HideMessage(message, conf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	recovered, err = RecoverMessage(shares[2:], conf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(recovered, message) {
+		t.Errorf("expected %s, got %s", message, recovered)
+	}
+	// unsupported encoding
+	if _, err := HideMessage(message, &Config{Shares: 5, Min: 3, Encoding: Encoding(7)}); err != ErrConfigEncoding {
+		t.Errorf("expected %v, got %v", ErrConfigEncoding, err)
+	}
+}
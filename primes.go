@@ -0,0 +1,133 @@
+package gosss
+
+import (
+	"crypto/elliptic"
+	"encoding/binary"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Names of the prime numbers registered by default. Every prime is the order
+// of the scalar field of a well known curve, or a Mersenne prime.
+const (
+	PrimeBN254       = "bn254"
+	PrimeBLS12381    = "bls12-381"
+	PrimeSecp256k1   = "secp256k1"
+	PrimeP256        = "p256"
+	PrimeCurve25519  = "curve25519"
+	PrimeMersenne127 = "mersenne127"
+	PrimeMersenne521 = "mersenne521"
+)
+
+// primes is the registry of named prime numbers that can be selected by name
+// in the configuration. It is protected by primesMtx, so new primes can be
+// registered at any time.
+var (
+	primesMtx sync.RWMutex
+	primes    = map[string]*big.Int{
+		PrimeBN254:       DefaultPrime,
+		PrimeBLS12381:    mustParsePrime("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001"),
+		PrimeSecp256k1:   mustParsePrime("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141"),
+		PrimeP256:        elliptic.P256().Params().N,
+		PrimeCurve25519:  mustParsePrime("1000000000000000000000000000000014def9dea2f79cd65812631a5cf5d3ed"),
+		PrimeMersenne127: mersennePrime(127),
+		PrimeMersenne521: mersennePrime(521),
+	}
+)
+
+// RegisterPrime adds the prime number provided to the registry with the name
+// provided, so it can be selected by name in the configuration. The name is
+// embedded in the shares, so it must be registered with the same prime before
+// recovering them. It returns an error if the name is empty or already
+// registered, or if the prime number is not valid.
+func RegisterPrime(name string, prime *big.Int) error {
+	if name == "" {
+		return ErrConfigPrimeName
+	}
+	if err := (&Config{Prime: prime}).ValidPrime(); err != nil {
+		return err
+	}
+	primesMtx.Lock()
+	defer primesMtx.Unlock()
+	if _, ok := primes[name]; ok {
+		return ErrConfigPrimeName
+	}
+	primes[name] = new(big.Int).Set(prime)
+	return nil
+}
+
+// LookupPrime returns the prime number registered with the name provided. It
+// returns an error if the name is not registered.
+func LookupPrime(name string) (*big.Int, error) {
+	primesMtx.RLock()
+	defer primesMtx.RUnlock()
+	prime, ok := primes[name]
+	if !ok {
+		return nil, ErrConfigPrimeName
+	}
+	return new(big.Int).Set(prime), nil
+}
+
+// PrimeNames returns the names of the registered prime numbers, sorted.
+func PrimeNames() []string {
+	primesMtx.RLock()
+	defer primesMtx.RUnlock()
+	names := make([]string, 0, len(primes))
+	for name := range primes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// smallestPrime returns the name of the smallest registered prime number with
+// at least the minimum number of bits provided that fits a message of the
+// length provided in a single chunk, including its length prefix, so the
+// shares are as short as possible. If the message does not fit in a single
+// chunk of any prime, it returns the largest one, which needs the fewest
+// chunks. It returns an error if no prime reaches the minimum number of bits.
+func smallestPrime(messageLen, minBits int) (string, error) {
+	encodedLen := len(binary.AppendUvarint(nil, uint64(messageLen))) + messageLen
+	primesMtx.RLock()
+	defer primesMtx.RUnlock()
+	// sort the candidates by value, breaking ties by name
+	candidates := []string{}
+	for name, prime := range primes {
+		if prime.BitLen() >= minBits {
+			candidates = append(candidates, name)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", ErrConfigPrimeTooSmall
+	}
+	slices.SortFunc(candidates, func(a, b string) int {
+		if c := primes[a].Cmp(primes[b]); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+	for _, name := range candidates {
+		if len(primes[name].Bytes())-1 >= encodedLen {
+			return name, nil
+		}
+	}
+	return candidates[len(candidates)-1], nil
+}
+
+// mustParsePrime returns the prime number encoded in hexadecimal provided. It
+// panics if it cannot be parsed, so it must only be used with constants.
+func mustParsePrime(hexPrime string) *big.Int {
+	prime, ok := new(big.Int).SetString(hexPrime, 16)
+	if !ok {
+		panic("invalid prime: " + hexPrime)
+	}
+	return prime
+}
+
+// mersennePrime returns the Mersenne number 2^n - 1.
+func mersennePrime(n uint) *big.Int {
+	prime := new(big.Int).Lsh(big.NewInt(1), n)
+	return prime.Sub(prime, big.NewInt(1))
+}
@@ -0,0 +1,180 @@
+package gosss
+
+import (
+	"bytes"
+	"math/big"
+	"slices"
+	"testing"
+)
+
+func TestRegisteredPrimes(t *testing.T) {
+	names := PrimeNames()
+	if !slices.IsSorted(names) {
+		t.Errorf("unexpected order: %v", names)
+	}
+	for _, name := range []string{PrimeBN254, PrimeBLS12381, PrimeSecp256k1, PrimeP256,
+		PrimeCurve25519, PrimeMersenne127, PrimeMersenne521} {
+		if !slices.Contains(names, name) {
+			t.Errorf("prime %s not registered", name)
+		}
+		prime, err := LookupPrime(name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !prime.ProbablyPrime(20) {
+			t.Errorf("%s is not a prime: %v", name, prime)
+		}
+	}
+	if prime, _ := LookupPrime(PrimeBN254); prime.Cmp(DefaultPrime) != 0 {
+		t.Errorf("expected %v, got %v", DefaultPrime, prime)
+	}
+	if _, err := LookupPrime("unknown"); err != ErrConfigPrimeName {
+		t.Errorf("expected %v, got %v", ErrConfigPrimeName, err)
+	}
+}
+
+func TestRegisterPrime(t *testing.T) {
+	if err := RegisterPrime("test-10007", big.NewInt(10007)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := RegisterPrime("test-10007", big.NewInt(10007)); err != ErrConfigPrimeName {
+		t.Errorf("expected %v, got %v", ErrConfigPrimeName, err)
+	}
+	if err := RegisterPrime("", big.NewInt(10007)); err != ErrConfigPrimeName {
+		t.Errorf("expected %v, got %v", ErrConfigPrimeName, err)
+	}
+	if err := RegisterPrime("test-10008", big.NewInt(10008)); err != ErrConfigInvalidPrime {
+		t.Errorf("expected %v, got %v", ErrConfigInvalidPrime, err)
+	}
+}
+
+func TestHideRecoverNamedPrime(t *testing.T) {
+	message := bytes.Repeat(examplePrivateMessage, 3)
+	for _, name := range PrimeNames() {
+		if name == "test-10007" {
+			continue
+		}
+		shares, err := HideMessage(message, &Config{Shares: 5, Min: 3, PrimeName: name})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// the name of the prime is taken from the shares
+		recovered, err := RecoverMessage(shares[:3], nil)
+		if err != nil {
+			t.Fatalf("unexpected error with %s: %v", name, err)
+		}
+		if !bytes.Equal(recovered, message) {
+			t.Errorf("unexpected message with %s: %s", name, recovered)
+		}
+	}
+	shares, err := HideMessage(message, &Config{Shares: 5, Min: 3, PrimeName: PrimeSecp256k1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// a different prime in the configuration is not overridden by the name
+	if _, err := RecoverMessage(shares[:3], &Config{Prime: DefaultPrime}); err != ErrPrimeMismatch {
+		t.Errorf("expected %v, got %v", ErrPrimeMismatch, err)
+	}
+	// the name and the prime must match
+	conf := &Config{Shares: 5, Min: 3, Prime: DefaultPrime, PrimeName: PrimeSecp256k1}
+	if _, err := HideMessage(message, conf); err != ErrConfigPrimeName {
+		t.Errorf("expected %v, got %v", ErrConfigPrimeName, err)
+	}
+	if _, err := HideMessage(message, &Config{Shares: 5, Min: 3, PrimeName: "unknown"}); err != ErrConfigPrimeName {
+		t.Errorf("expected %v, got %v", ErrConfigPrimeName, err)
+	}
+}
+
+func Test_smallestPrime(t *testing.T) {
+	tests := []struct {
+		messageLen int
+		minBits    int
+		expected   string
+		err        error
+	}{
+		{10, 0, PrimeMersenne127, nil},
+		{20, 0, PrimeCurve25519, nil},
+		{40, 0, PrimeMersenne521, nil},
+		{1000, 0, PrimeMersenne521, nil},
+		{10, 256, PrimeP256, nil},
+		{10, 600, "", ErrConfigPrimeTooSmall},
+	}
+	for _, test := range tests {
+		name, err := smallestPrime(test.messageLen, test.minBits)
+		if err != test.err {
+			t.Errorf("expected %v, got %v", test.err, err)
+		}
+		if name != test.expected {
+			t.Errorf("expected %s, got %s", test.expected, name)
+		}
+	}
+}
+
+func TestHideMessageAutoPrime(t *testing.T) {
+	message := []byte("short message")
+	conf := &Config{Shares: 5, Min: 3, AutoPrime: true}
+	shares, err := HideMessageShares(message, conf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if shares[0].PrimeName != PrimeMersenne127 || len(shares[0].Values) != 1 {
+		t.Errorf("unexpected prime or chunks: %s %d", shares[0].PrimeName, len(shares[0].Values))
+	}
+	recovered, err := RecoverMessageShares(shares[:3], nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(recovered, message) {
+		t.Errorf("expected %s, got %s", message, recovered)
+	}
+	// the minimum number of bits is honoured by the selection and required
+	// for the primes provided
+	conf = &Config{Shares: 5, Min: 3, AutoPrime: true, MinPrimeBits: 200}
+	if shares, err = HideMessageShares(message, conf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if shares[0].PrimeName != PrimeCurve25519 {
+		t.Errorf("expected %s, got %s", PrimeCurve25519, shares[0].PrimeName)
+	}
+	conf = &Config{Shares: 5, Min: 3, PrimeName: PrimeMersenne127, MinPrimeBits: 200}
+	if _, err := HideMessageShares(message, conf); err != ErrConfigPrimeTooSmall {
+		t.Errorf("expected %v, got %v", ErrConfigPrimeTooSmall, err)
+	}
+	// the configuration is not modified, so it selects the prime number again
+	// for every message
+	conf = &Config{Shares: 5, Min: 3, AutoPrime: true}
+	if _, err := HideMessageShares(message, conf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conf.Prime != nil || conf.PrimeName != "" {
+		t.Errorf("unexpected prime selected in the configuration: %v %s", conf.Prime, conf.PrimeName)
+	}
+	longMessage := bytes.Repeat([]byte("a"), 40)
+	if shares, err = HideMessageShares(longMessage, conf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if shares[0].PrimeName != PrimeMersenne521 || len(shares[0].Values) != 1 {
+		t.Errorf("unexpected prime or chunks: %s %d", shares[0].PrimeName, len(shares[0].Values))
+	}
+}
+
+func TestHideVerifiableMessageAutoPrime(t *testing.T) {
+	message := []byte("short message")
+	// the smallest prime of 254 bits is the default one, which has a group
+	conf := &Config{Shares: 5, Min: 3, AutoPrime: true, MinPrimeBits: 254}
+	shares, commitments, err := HideVerifiableMessage(message, conf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if shares[0].PrimeName != PrimeBN254 {
+		t.Errorf("expected %s, got %s", PrimeBN254, shares[0].PrimeName)
+	}
+	if err := VerifyShare(shares[0], commitments, nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	// the smallest prime has no group
+	conf = &Config{Shares: 5, Min: 3, AutoPrime: true}
+	if _, _, _, err := HidePedersenMessage(message, conf); err != ErrConfigNoGroup {
+		t.Errorf("expected %v, got %v", ErrConfigNoGroup, err)
+	}
+}
@@ -0,0 +1,202 @@
+package gosss
+
+import (
+	"bytes"
+	"math/big"
+)
+
+// RedistributeShare runs the step of a holder of the quorum in the protocol to
+// redistribute the shares of a message to a new configuration, with a
+// different number of shares and threshold, without reconstructing the
+// message. The quorum is the list of the indexes of the current holders that
+// take part in the protocol, which must reach the threshold of the set and
+// include the index of the share provided. The holder multiplies its share by
+// its Lagrange basis polynomial evaluated at zero, so the contributions of the
+// quorum add up to the message, and shares the result with a new random
+// polynomial of the new threshold, evaluated at the index of every new
+// holder. It returns a sub-share for every new holder, which must be sent
+// only to it. The sub-shares belong to the same set of the share, with the
+// next epoch and the threshold and number of shares of the new
+// configuration, so the new shares can not be mixed with the old ones. It
+// returns an error if the share is a legacy share, if the new configuration
+// is not valid, if the prime number is not the one of the share or if the
+// quorum is not valid.
+func RedistributeShare(share Share, quorum []*big.Int, conf *Config) ([]Share, error) {
+	if conf == nil {
+		return nil, ErrRequiredConfig
+	}
+	conf = sharesConfig([]Share{share}, conf)
+	if err := conf.ValidConfig(nil); err != nil {
+		return nil, err
+	}
+	if err := checkShares([]Share{share}, conf); err != nil {
+		return nil, err
+	}
+	if share.legacy() {
+		return nil, ErrLegacyShare
+	}
+	position, err := quorumPosition(share, quorum)
+	if err != nil {
+		return nil, err
+	}
+	// every chunk of the share is multiplied by the Lagrange basis of the
+	// holder at zero and shared with a new random polynomial
+	basis := lagrangeBasis(quorum, position, conf.Prime, big.NewInt(0))
+	var xs []*big.Int
+	chunksYs := make([][]*big.Int, len(share.Values))
+	for i, value := range share.Values {
+		contribution := new(big.Int).Mul(value, basis)
+		contribution.Mod(contribution, conf.Prime)
+		coeffs, err := calcCoeffs(contribution, conf.Prime, conf.Min)
+		if err != nil {
+			return nil, err
+		}
+		xs, chunksYs[i] = calcShares(coeffs, conf.Shares, conf.Prime)
+	}
+	subShares := make([]Share, len(xs))
+	for i := range xs {
+		subShares[i] = Share{
+			Index:     xs[i],
+			Values:    make([]*big.Int, len(chunksYs)),
+			SetID:     share.SetID,
+			PrimeID:   share.PrimeID,
+			PrimeName: share.PrimeName,
+			Threshold: conf.Min,
+			Total:     conf.Shares,
+			Epoch:     share.Epoch + 1,
+		}
+		for j := range chunksYs {
+			subShares[i].Values[j] = chunksYs[j][i]
+		}
+	}
+	return subShares, nil
+}
+
+// CombineRedistributedShares runs the step of a new holder in the protocol to
+// redistribute the shares of a message. It adds up the sub-shares received
+// from every holder of the quorum, which must have been generated with
+// RedistributeShare for the same new holder, and returns its new share. The
+// sub-share of every holder of the quorum is required, otherwise the new
+// share will not be valid. It uses the configuration provided in the Config
+// struct, if the prime number is not defined it uses the bn254 𝔽r prime as
+// default. It returns an error if no sub-share is provided or if they are not
+// for the same new holder and set.
+func CombineRedistributedShares(subShares []Share, conf *Config) (Share, error) {
+	return addShares(subShares, conf)
+}
+
+// RedistributeShares redistributes the shares provided to a new configuration
+// running every step of the redistribution protocol locally: every share
+// provided is part of the quorum and generates its sub-shares with
+// RedistributeShare, and the new share of every new holder is combined with
+// CombineRedistributedShares. It is useful when a single party has access to
+// the quorum, for example to test the protocol, but in that case the party
+// could also recover the message, so the protocol should be run by every
+// holder on its own to keep the message hidden. It returns the new shares or
+// an error if any step fails.
+func RedistributeShares(shares []Share, conf *Config) ([]Share, error) {
+	quorum := sharesIndexes(shares)
+	received := [][]Share{}
+	for _, share := range shares {
+		subShares, err := RedistributeShare(share, quorum, conf)
+		if err != nil {
+			return nil, err
+		}
+		if len(received) == 0 {
+			received = make([][]Share, len(subShares))
+		}
+		for i, subShare := range subShares {
+			received[i] = append(received[i], subShare)
+		}
+	}
+	newShares := make([]Share, len(received))
+	for i, subShares := range received {
+		var err error
+		if newShares[i], err = CombineRedistributedShares(subShares, conf); err != nil {
+			return nil, err
+		}
+	}
+	return newShares, nil
+}
+
+// sharesIndexes returns the indexes of the shares provided, in the same
+// order, to be used as the quorum of a protocol run locally.
+func sharesIndexes(shares []Share) []*big.Int {
+	indexes := make([]*big.Int, len(shares))
+	for i, share := range shares {
+		indexes[i] = share.Index
+	}
+	return indexes
+}
+
+// quorumPosition returns the position of the index of the share provided in
+// the quorum. It returns an error if the quorum does not reach the threshold
+// of the share, if it includes duplicated or nil indexes or if it does not
+// include the index of the share.
+func quorumPosition(share Share, quorum []*big.Int) (int, error) {
+	if len(quorum) < share.Threshold {
+		return 0, ErrNotEnoughShares
+	}
+	position := -1
+	seen := map[string]bool{}
+	for i, index := range quorum {
+		if index == nil {
+			return 0, ErrInvalidShare
+		}
+		if seen[index.String()] {
+			return 0, ErrDuplicatedShare
+		}
+		seen[index.String()] = true
+		if index.Cmp(share.Index) == 0 {
+			position = i
+		}
+	}
+	if position < 0 {
+		return 0, ErrNotInQuorum
+	}
+	return position, nil
+}
+
+// addShares adds up the values of the shares provided, which must have the
+// same index and belong to the same set, and returns the resulting share with
+// the metadata of the first one. It is used by the holders to combine the
+// contributions received in the redistribution, enrollment and repair
+// protocols. It returns an error if no share is provided or if they can not
+// be added together.
+func addShares(subShares []Share, conf *Config) (Share, error) {
+	conf = sharesConfig(subShares, conf)
+	if err := conf.ValidPrime(); err != nil {
+		return Share{}, err
+	}
+	if len(subShares) == 0 {
+		return Share{}, ErrNotEnoughShares
+	}
+	first := subShares[0]
+	combined := first
+	combined.Values = make([]*big.Int, len(first.Values))
+	for i := range combined.Values {
+		combined.Values[i] = big.NewInt(0)
+	}
+	for _, subShare := range subShares {
+		// every sub-share must be for the same holder and set, so they are
+		// checked one by one with the first one
+		if err := checkShares([]Share{subShare}, conf); err != nil {
+			return Share{}, err
+		}
+		if subShare.legacy() {
+			return Share{}, ErrLegacyShare
+		}
+		if subShare.Index.Cmp(first.Index) != 0 || len(subShare.Values) != len(first.Values) {
+			return Share{}, ErrInvalidShare
+		}
+		if !bytes.Equal(subShare.SetID, first.SetID) || subShare.Epoch != first.Epoch ||
+			subShare.Threshold != first.Threshold || subShare.Total != first.Total {
+			return Share{}, ErrShareSetMismatch
+		}
+		for i, value := range subShare.Values {
+			combined.Values[i].Add(combined.Values[i], value)
+			combined.Values[i].Mod(combined.Values[i], conf.Prime)
+		}
+	}
+	return combined, nil
+}
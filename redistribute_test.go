@@ -0,0 +1,119 @@
+package gosss
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestRedistributeShares(t *testing.T) {
+	config := &Config{
+		Shares: 5,
+		Min:    3,
+	}
+	message := bytes.Repeat(examplePrivateMessage, 3)
+	shares, err := HideMessageShares(message, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// redistribute to 7 shares with threshold 4 using a quorum of 3 holders
+	newConfig := &Config{
+		Shares: 7,
+		Min:    4,
+	}
+	newShares, err := RedistributeShares(shares[1:4], newConfig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(newShares) != newConfig.Shares {
+		t.Fatalf("unexpected number of shares: %d", len(newShares))
+	}
+	for _, share := range newShares {
+		if share.Threshold != newConfig.Min || share.Total != newConfig.Shares || share.Epoch != shares[0].Epoch+1 {
+			t.Errorf("unexpected metadata: %d %d %d", share.Threshold, share.Total, share.Epoch)
+		}
+	}
+	recovered, err := RecoverMessageShares(newShares[3:], newConfig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(recovered, message) {
+		t.Errorf("unexpected message: %s", recovered)
+	}
+	// the new threshold is required
+	if _, err := RecoverMessageShares(newShares[:3], newConfig); err != ErrNotEnoughShares {
+		t.Errorf("expected %v, got %v", ErrNotEnoughShares, err)
+	}
+	// old and new shares can not be mixed
+	mixed := []Share{shares[0], newShares[1], newShares[2], newShares[3]}
+	if _, err := RecoverMessageShares(mixed, newConfig); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestRedistributeShare(t *testing.T) {
+	config := &Config{
+		Shares: 4,
+		Min:    3,
+	}
+	shares, err := HideMessageShares(examplePrivateMessage, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	quorum := []*big.Int{shares[0].Index, shares[1].Index, shares[2].Index}
+	// the quorum does not reach the threshold
+	if _, err := RedistributeShare(shares[0], quorum[:2], config); err != ErrNotEnoughShares {
+		t.Errorf("expected %v, got %v", ErrNotEnoughShares, err)
+	}
+	// the share is not part of the quorum
+	if _, err := RedistributeShare(shares[3], quorum, config); err != ErrNotInQuorum {
+		t.Errorf("expected %v, got %v", ErrNotInQuorum, err)
+	}
+	// duplicated index in the quorum
+	duplicated := []*big.Int{shares[0].Index, shares[0].Index, shares[1].Index}
+	if _, err := RedistributeShare(shares[0], duplicated, config); err != ErrDuplicatedShare {
+		t.Errorf("expected %v, got %v", ErrDuplicatedShare, err)
+	}
+	// sub-shares of different holders can not be combined
+	subShares, err := RedistributeShare(shares[0], quorum, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := CombineRedistributedShares(subShares[:2], config); err != ErrInvalidShare {
+		t.Errorf("expected %v, got %v", ErrInvalidShare, err)
+	}
+}
+
+func TestRedistributeSharesNamedPrime(t *testing.T) {
+	config := &Config{
+		Shares:    5,
+		Min:       3,
+		PrimeName: PrimeSecp256k1,
+	}
+	shares, err := HideMessageShares(examplePrivateMessage, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the new configuration does not define the prime, it is taken from the
+	// name of the shares
+	newConfig := &Config{
+		Shares: 6,
+		Min:    4,
+	}
+	newShares, err := RedistributeShares(shares[:3], newConfig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, share := range newShares {
+		if share.PrimeName != PrimeSecp256k1 {
+			t.Errorf("expected %s, got %s", PrimeSecp256k1, share.PrimeName)
+		}
+	}
+	recovered, err := RecoverMessageShares(newShares[2:], nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(recovered, examplePrivateMessage) {
+		t.Errorf("expected %s, got %s", examplePrivateMessage, recovered)
+	}
+}
@@ -0,0 +1,53 @@
+package gosss
+
+import "math/big"
+
+// RefreshShares updates the shares provided without reconstructing the
+// message, so the shares that leaked in the past become useless. For every
+// chunk, it generates a random polynomial of the same degree whose first
+// coefficient is zero, and adds its evaluation at the index of every share to
+// the value of the share. Since the new polynomial is the sum of both, it
+// hides the same message, but the points of the old and the new polynomial
+// cannot be mixed to recover it. The epoch of the refreshed shares is
+// incremented, so RecoverMessage rejects sets of shares of different epochs.
+// Every share of the set that must remain valid has to be refreshed at the
+// same time, the shares not provided will not be usable with the refreshed
+// ones. The commitments generated for the previous epoch do not verify the
+// refreshed shares. It uses the configuration provided in the Config struct,
+// if the prime number is not defined it uses the bn254 𝔽r prime as default.
+// It returns an error if the shares cannot be used together, if they are
+// legacy shares, which do not include the epoch, or if the random
+// coefficients cannot be generated.
+func RefreshShares(shares []Share, conf *Config) ([]Share, error) {
+	conf = sharesConfig(shares, conf)
+	if err := conf.ValidPrime(); err != nil {
+		return nil, err
+	}
+	// the shares do not need to reach the threshold to be refreshed
+	if err := checkShares(shares, conf); err != nil {
+		return nil, err
+	}
+	if shares[0].legacy() {
+		return nil, ErrLegacyShare
+	}
+	refreshed := make([]Share, len(shares))
+	for i, share := range shares {
+		refreshed[i] = share
+		refreshed[i].Values = make([]*big.Int, len(share.Values))
+		refreshed[i].Epoch = share.Epoch + 1
+	}
+	for j := range shares[0].Values {
+		// calculate a random polynomial with zero as first coefficient and the
+		// same degree of the polynomial of the chunk
+		coeffs, err := calcCoeffs(big.NewInt(0), conf.Prime, shares[0].Threshold)
+		if err != nil {
+			return nil, err
+		}
+		for i, share := range shares {
+			update := solvePolynomial(coeffs, share.Index, conf.Prime)
+			refreshed[i].Values[j] = update.Add(update, share.Values[j])
+			refreshed[i].Values[j].Mod(refreshed[i].Values[j], conf.Prime)
+		}
+	}
+	return refreshed, nil
+}
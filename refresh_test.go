@@ -0,0 +1,56 @@
+package gosss
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestRefreshShares(t *testing.T) {
+	config := &Config{
+		Shares: 5,
+		Min:    3,
+	}
+	message := bytes.Repeat(examplePrivateMessage, 3)
+	shares, err := HideMessageShares(message, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	refreshed, err := RefreshShares(shares, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := range refreshed {
+		if refreshed[i].Epoch != shares[i].Epoch+1 {
+			t.Errorf("unexpected epoch: %d", refreshed[i].Epoch)
+		}
+		if refreshed[i].Values[0].Cmp(shares[i].Values[0]) == 0 {
+			t.Errorf("expected updated value")
+		}
+	}
+	// the refreshed shares recover the same message, also after encoding them
+	text, err := refreshed[4].MarshalText()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := refreshed[4].UnmarshalText(text); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	recovered, err := RecoverMessageShares(refreshed[2:], config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(recovered, message) {
+		t.Errorf("unexpected message: %s", recovered)
+	}
+	// old and new shares can not be mixed
+	mixed := []Share{shares[0], shares[1], refreshed[2]}
+	if _, err := RecoverMessageShares(mixed, config); err != ErrEpochMismatch {
+		t.Errorf("expected %v, got %v", ErrEpochMismatch, err)
+	}
+	// legacy shares can not be refreshed
+	legacy := []Share{{Index: big.NewInt(1), Values: []*big.Int{big.NewInt(1)}}}
+	if _, err := RefreshShares(legacy, config); err != ErrLegacyShare {
+		t.Errorf("expected %v, got %v", ErrLegacyShare, err)
+	}
+}
@@ -0,0 +1,30 @@
+package gosss
+
+import (
+	"math/big"
+)
+
+// RepairShare runs the first step of a holder of the quorum in the protocol
+// to repair a lost share, regenerating the share of the same index without
+// reconstructing the message. The protocol is the same of the enrollment of a
+// new holder, using the index of the lost share as the new index: the holder
+// multiplies its share by its Lagrange basis polynomial evaluated at the lost
+// index and splits the result into random pieces, one for every holder of the
+// quorum, so neither the message nor the shares of the holders are revealed.
+// Every holder adds up the pieces received with CombineEnrollmentShares and
+// sends the result to the holder of the lost share, which adds them up to get
+// its share back. The repaired share has the same metadata of the share
+// provided, so the holders of the quorum must be in the same epoch of the lost
+// share. It returns an error if the share is a legacy share, if the quorum is
+// not valid or if the lost index is not valid or is part of the quorum.
+func RepairShare(share Share, quorum []*big.Int, lostIndex *big.Int, conf *Config) ([]Share, error) {
+	return EnrollShare(share, quorum, lostIndex, conf)
+}
+
+// RepairShares regenerates the lost share with the index provided running
+// every step of the repair protocol locally with the shares provided as the
+// quorum, see EnrollShares. It returns the repaired share or an error if any
+// step fails.
+func RepairShares(shares []Share, lostIndex *big.Int, conf *Config) (Share, error) {
+	return EnrollShares(shares, lostIndex, conf)
+}
@@ -0,0 +1,43 @@
+package gosss
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRepairShares(t *testing.T) {
+	config := &Config{
+		Shares: 5,
+		Min:    3,
+	}
+	message := bytes.Repeat(examplePrivateMessage, 3)
+	shares, err := HideMessageShares(message, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the holder of the first share loses it
+	lost := shares[0]
+	repaired, err := RepairShares(shares[2:], lost.Index, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repaired.String() != lost.String() {
+		t.Errorf("expected %s, got %s", lost, repaired)
+	}
+	recovered, err := RecoverMessageShares([]Share{repaired, shares[1], shares[4]}, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(recovered, message) {
+		t.Errorf("unexpected message: %s", recovered)
+	}
+	// the lost index can not be part of the quorum
+	quorum := sharesIndexes(shares[:3])
+	if _, err := RepairShare(shares[1], quorum, lost.Index, config); err != ErrInvalidIndex {
+		t.Errorf("expected %v, got %v", ErrInvalidIndex, err)
+	}
+	// the quorum must reach the threshold
+	if _, err := RepairShares(shares[3:], lost.Index, config); err != ErrNotEnoughShares {
+		t.Errorf("expected %v, got %v", ErrNotEnoughShares, err)
+	}
+}
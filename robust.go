@@ -0,0 +1,207 @@
+package gosss
+
+import (
+	"math/big"
+	"slices"
+)
+
+// RecoverMessageRobust recovers the message from the shares provided as
+// strings, correcting up to (n - k) / 2 faulty shares, where n is the number
+// of shares provided and k is the threshold of the set. It decodes the shares
+// and uses RecoverMessageSharesRobust to recover the message, so it returns
+// the same results. If any share cannot be decoded, it returns a ShareError
+// with the position of the share in the input.
+func RecoverMessageRobust(inputs []string, conf *Config) ([]byte, []int, error) {
+	shares := make([]Share, len(inputs))
+	for i, input := range inputs {
+		if err := shares[i].UnmarshalText([]byte(input)); err != nil {
+			return nil, nil, &ShareError{Position: i, Err: err}
+		}
+	}
+	return RecoverMessageSharesRobust(shares, conf)
+}
+
+// RecoverMessageSharesRobust recovers the message from the shares provided,
+// correcting up to (n - k) / 2 faulty shares, where n is the number of shares
+// provided and k is the threshold of the set. The limit applies to every
+// chunk independently, so more shares can be faulty if their wrong points are
+// in different chunks. Instead of trusting every share like
+// RecoverMessageShares does, it decodes the points of every chunk as a
+// Reed-Solomon code using the Berlekamp-Welch algorithm, which finds the
+// polynomial of degree k - 1 that crosses every point except the faulty ones.
+// It returns the recovered message and the positions in the input of the
+// shares identified as faulty, sorted. The threshold is taken from the
+// metadata of the shares, or from the minimum number of shares of the
+// configuration for legacy shares. It returns an error if the threshold is
+// not known, if the shares cannot be used together or if there are too many
+// faulty shares to correct them.
+func RecoverMessageSharesRobust(shares []Share, conf *Config) ([]byte, []int, error) {
+	// prepare the configuration to recover the message
+	conf = sharesConfig(shares, conf)
+	if err := conf.ValidPrime(); err != nil {
+		return nil, nil, err
+	}
+	// check that the shares can be used together
+	if err := checkShares(shares, conf); err != nil {
+		return nil, nil, err
+	}
+	// get the threshold of the set of shares
+	k, err := sharesThreshold(shares, conf)
+	if err != nil {
+		return nil, nil, err
+	}
+	// decode the polynomial of every chunk and collect the shares whose
+	// points are not on any of them
+	xs, chunksYs := sharesPoints(shares)
+	faulty := []int{}
+	chunks := make([]*big.Int, len(chunksYs))
+	for i, ys := range chunksYs {
+		coeffs, err := berlekampWelch(xs, ys, k, conf.Prime)
+		if err != nil {
+			return nil, nil, err
+		}
+		chunks[i] = coeffs[0]
+		for j, x := range xs {
+			if solvePolynomial(coeffs, x, conf.Prime).Cmp(ys[j]) != 0 && !slices.Contains(faulty, j) {
+				faulty = append(faulty, j)
+			}
+		}
+	}
+	slices.Sort(faulty)
+	message, err := decodeChunks(chunks, shares[0].legacy(), conf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return message, faulty, nil
+}
+
+// ConsistencyReport struct contains the result of checking the consistency of
+// a set of shares with VerifyConsistency. It includes the positions in the
+// input of the shares whose points lie on the polynomials interpolated from
+// the first k shares, including them, and the positions of the shares whose
+// points do not.
+type ConsistencyReport struct {
+	Consistent   []int
+	Inconsistent []int
+}
+
+// Ok returns true if every share provided is consistent.
+func (r *ConsistencyReport) Ok() bool {
+	return len(r.Inconsistent) == 0
+}
+
+// VerifyConsistency checks that the shares provided lie on the same
+// polynomials. It interpolates the polynomial of every chunk from the first k
+// shares, where k is the threshold of the set, and checks that the points of
+// every remaining share lie on them, so the redundancy of providing more
+// shares than the threshold is used to detect wrong shares instead of
+// silently using all of them to recover the message. The threshold is taken
+// from the metadata of the shares, or from the minimum number of shares of
+// the configuration for legacy shares. If any of the first k shares is wrong,
+// every remaining share is reported as inconsistent, so to identify the wrong
+// shares RecoverMessageSharesRobust should be used. It returns an error if the
+// threshold is not known or if the shares cannot be used together.
+func VerifyConsistency(shares []Share, conf *Config) (*ConsistencyReport, error) {
+	conf = sharesConfig(shares, conf)
+	if err := conf.ValidPrime(); err != nil {
+		return nil, err
+	}
+	if err := checkShares(shares, conf); err != nil {
+		return nil, err
+	}
+	k, err := sharesThreshold(shares, conf)
+	if err != nil {
+		return nil, err
+	}
+	xs, chunksYs := sharesPoints(shares)
+	report := &ConsistencyReport{Consistent: []int{}, Inconsistent: []int{}}
+	for i := range k {
+		report.Consistent = append(report.Consistent, i)
+	}
+	for i := k; i < len(shares); i++ {
+		consistent := true
+		for _, ys := range chunksYs {
+			y := lagrangeInterpolation(xs[:k], ys[:k], conf.Prime, xs[i])
+			if y.Cmp(new(big.Int).Mod(ys[i], conf.Prime)) != 0 {
+				consistent = false
+				break
+			}
+		}
+		if consistent {
+			report.Consistent = append(report.Consistent, i)
+		} else {
+			report.Inconsistent = append(report.Inconsistent, i)
+		}
+	}
+	return report, nil
+}
+
+// sharesThreshold returns the minimum number of shares to recover the message
+// of the shares provided. It is taken from the metadata of the shares, or from
+// the minimum number of shares of the configuration for legacy shares. It
+// returns an error if the threshold is not valid or if there are not enough
+// shares provided.
+func sharesThreshold(shares []Share, conf *Config) (int, error) {
+	k := shares[0].Threshold
+	if k == 0 {
+		k = conf.Min
+	}
+	if k < MinMinShares {
+		return 0, ErrConfigMin
+	}
+	if len(shares) < k {
+		return 0, ErrNotEnoughShares
+	}
+	return k, nil
+}
+
+// berlekampWelch decodes the polynomial of degree k - 1 that crosses the
+// points provided, except up to e = (n - k) / 2 of them, where n is the number
+// of points. It looks for the error locator polynomial E(x), monic and of
+// degree e, whose roots are the x coordinates of the wrong points, and the
+// polynomial Q(x) = P(x) * E(x), of degree e + k - 1, such that:
+//
+//	Q(x_i) = y_i * E(x_i), for every point i
+//
+// which is a system of n linear equations with 2e + k unknowns, the
+// coefficients of Q(x) and the e lowest coefficients of E(x). Then, the
+// polynomial P(x) is the quotient of Q(x) and E(x). It returns the
+// coefficients of P(x) or an error if there are too many wrong points.
+func berlekampWelch(xs, ys []*big.Int, k int, prime *big.Int) ([]*big.Int, error) {
+	e := (len(xs) - k) / 2
+	nq := e + k
+	// build the augmented matrix of the system, every row is:
+	// [1, x, ..., x^(e+k-1), -y, -y*x, ..., -y*x^(e-1) | y*x^e]
+	matrix := make([][]*big.Int, len(xs))
+	for i, x := range xs {
+		row := make([]*big.Int, nq+e+1)
+		pow := big.NewInt(1)
+		for j := 0; j < nq; j++ {
+			row[j] = new(big.Int).Set(pow)
+			if j < e {
+				row[nq+j] = new(big.Int).Mul(ys[i], pow)
+				row[nq+j].Neg(row[nq+j]).Mod(row[nq+j], prime)
+			}
+			if j == e {
+				row[nq+e] = new(big.Int).Mul(ys[i], pow)
+				row[nq+e].Mod(row[nq+e], prime)
+			}
+			pow = new(big.Int).Mul(pow, x)
+			pow.Mod(pow, prime)
+		}
+		matrix[i] = row
+	}
+	solution, ok := solveLinearSystem(matrix, prime)
+	if !ok {
+		return nil, ErrTooManyFaultyShares
+	}
+	// divide Q(x) by E(x), including the leading coefficient of E(x)
+	errorLocator := append(solution[nq:], big.NewInt(1))
+	coeffs, remainder := dividePolynomials(solution[:nq], errorLocator, prime)
+	for _, r := range remainder {
+		if r.Sign() != 0 {
+			return nil, ErrTooManyFaultyShares
+		}
+	}
+	return coeffs[:k], nil
+}
@@ -0,0 +1,137 @@
+package gosss
+
+import (
+	"bytes"
+	"math/big"
+	"slices"
+	"testing"
+)
+
+func TestRecoverMessageSharesRobust(t *testing.T) {
+	config := &Config{
+		Shares: 9,
+		Min:    3,
+	}
+	message := bytes.Repeat(examplePrivateMessage, 4)
+	shares, err := HideMessageShares(message, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// without faulty shares
+	recovered, faulty, err := RecoverMessageSharesRobust(shares, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(recovered, message) || len(faulty) != 0 {
+		t.Errorf("unexpected result: %s %v", recovered, faulty)
+	}
+	// corrupt up to (n - k) / 2 = 3 shares, in different chunks
+	corrupted := slices.Clone(shares)
+	for i, position := range []int{1, 4, 7} {
+		corrupted[position].Values = slices.Clone(corrupted[position].Values)
+		chunk := i % len(corrupted[position].Values)
+		corrupted[position].Values[chunk] = new(big.Int).Add(corrupted[position].Values[chunk], big.NewInt(1))
+	}
+	recovered, faulty, err = RecoverMessageSharesRobust(corrupted, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(recovered, message) {
+		t.Errorf("unexpected message: %s", recovered)
+	}
+	if !slices.Equal(faulty, []int{1, 4, 7}) {
+		t.Errorf("unexpected faulty shares: %v", faulty)
+	}
+	// too many faulty shares in the same chunk
+	corrupted = slices.Clone(shares)
+	for _, position := range []int{0, 2, 3, 5} {
+		corrupted[position].Values = slices.Clone(corrupted[position].Values)
+		corrupted[position].Values[0] = new(big.Int).Add(corrupted[position].Values[0], big.NewInt(1))
+	}
+	if _, _, err := RecoverMessageSharesRobust(corrupted, config); err != ErrTooManyFaultyShares {
+		t.Errorf("expected %v, got %v", ErrTooManyFaultyShares, err)
+	}
+}
+
+func TestRecoverMessageRobust(t *testing.T) {
+	config := &Config{
+		Shares: 5,
+		Min:    3,
+	}
+	shares, err := HideMessage(examplePrivateMessage, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// replace a share by the share of other set with the same index, which
+	// is a valid share with a wrong point
+	otherShares, err := HideMessageShares(examplePrivateMessage, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var share Share
+	if err := share.UnmarshalText([]byte(shares[2])); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	share.Values = otherShares[2].Values
+	shares[2] = share.String()
+	recovered, faulty, err := RecoverMessageRobust(shares, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(recovered, examplePrivateMessage) || !slices.Equal(faulty, []int{2}) {
+		t.Errorf("unexpected result: %s %v", recovered, faulty)
+	}
+}
+
+func Test_berlekampWelch(t *testing.T) {
+	prime := big.NewInt(17)
+	// f(x) = (6 + x + 2x^2) % 17, with a wrong point at x = 3
+	coeffs := []*big.Int{big.NewInt(6), big.NewInt(1), big.NewInt(2)}
+	xs, ys := calcShares(coeffs, 5, prime)
+	ys[2] = new(big.Int).Add(ys[2], big.NewInt(1))
+	result, err := berlekampWelch(xs, ys, len(coeffs), prime)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := range coeffs {
+		if result[i].Cmp(coeffs[i]) != 0 {
+			t.Errorf("unexpected coefficient %d: %v", i, result[i])
+		}
+	}
+	// two wrong points can not be corrected with five points
+	ys[3] = new(big.Int).Add(ys[3], big.NewInt(1))
+	if result, err := berlekampWelch(xs, ys, len(coeffs), prime); err == nil && result[0].Cmp(coeffs[0]) == 0 {
+		t.Errorf("expected error or wrong polynomial, got %v", result)
+	}
+}
+
+func TestVerifyConsistency(t *testing.T) {
+	config := &Config{
+		Shares: 6,
+		Min:    3,
+	}
+	shares, err := HideMessageShares(examplePrivateMessage, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	report, err := VerifyConsistency(shares, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.Ok() || len(report.Consistent) != len(shares) {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	// corrupt a share out of the first k
+	shares[4].Values = []*big.Int{new(big.Int).Add(shares[4].Values[0], big.NewInt(1))}
+	report, err = VerifyConsistency(shares, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Ok() || !slices.Equal(report.Inconsistent, []int{4}) || !slices.Equal(report.Consistent, []int{0, 1, 2, 3, 5}) {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	// not enough shares
+	if _, err := VerifyConsistency(shares[:2], nil); err != ErrNotEnoughShares {
+		t.Errorf("expected %v, got %v", ErrNotEnoughShares, err)
+	}
+}
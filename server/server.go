@@ -0,0 +1,457 @@
+// Package server implements an HTTP server to collect the shares of a message
+// from remote holders. The owner opens a recovery session and sends its code
+// to the holders, who submit their shares through a JSON API. The shares are
+// validated as they arrive and, once there are enough of them, the message is
+// revealed only to the owner of the session, which is authenticated with a
+// secret token. The message is recovered correcting the wrong shares, and the
+// owner can drop shares and keep collecting them if it cannot be recovered,
+// so a forged share does not block the session. Every session is wiped after
+// the message is revealed or when it times out.
+package server
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lucasmenendez/gosss"
+)
+
+const (
+	// codeLen is the length in bytes of the session codes.
+	codeLen = 8
+	// tokenLen is the length in bytes of the owner tokens.
+	tokenLen = 32
+	// maxBodyLen is the maximum length in bytes of the body of a request.
+	maxBodyLen = 1 << 20
+	// maxSessions is the maximum number of sessions open at the same time,
+	// to bound the memory used by the server.
+	maxSessions = 64
+	// maxSessionShares is the maximum number of shares of a session. The
+	// total number of shares of the set is not used as the limit, because the
+	// holders enrolled after the split have shares beyond it.
+	maxSessionShares = 256
+)
+
+var (
+	// sessions
+	ErrSessionNotFound   = fmt.Errorf("session not found or expired")
+	ErrUnauthorized      = fmt.Errorf("invalid owner token")
+	ErrSessionIncomplete = fmt.Errorf("not enough shares submitted yet")
+	ErrSessionComplete   = fmt.Errorf("the session does not accept more shares")
+	ErrShareNotFound     = fmt.Errorf("no share with the index provided")
+	ErrTooManySessions   = fmt.Errorf("too many sessions open")
+	// requests
+	ErrInvalidRequest = fmt.Errorf("invalid request")
+)
+
+// Session struct contains the public information of a recovery session: its
+// code, the indexes of the shares accepted, the number of shares accepted and
+// required, which is zero until the first share is accepted, and when it
+// expires.
+type Session struct {
+	Code      string    `json:"code"`
+	Indexes   []string  `json:"indexes"`
+	Accepted  int       `json:"accepted"`
+	Required  int       `json:"required"`
+	Complete  bool      `json:"complete"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// session struct contains the state of a recovery session, including the
+// token of its owner and the shares accepted.
+type session struct {
+	code      string
+	token     string
+	expiresAt time.Time
+	timer     *time.Timer
+	inputs    []string
+	shares    []gosss.Share
+}
+
+// info returns the public information of the session.
+func (s *session) info() Session {
+	required := 0
+	if len(s.shares) > 0 {
+		required = s.shares[0].Threshold
+	}
+	indexes := make([]string, len(s.shares))
+	for i, share := range s.shares {
+		indexes[i] = share.Index.String()
+	}
+	return Session{
+		Code:      s.code,
+		Indexes:   indexes,
+		Accepted:  len(s.shares),
+		Required:  required,
+		Complete:  required > 0 && len(s.shares) >= required,
+		ExpiresAt: s.expiresAt,
+	}
+}
+
+// Server struct manages the recovery sessions and serves the JSON API to
+// open them, submit shares, check their status and reveal the message:
+//
+//	POST /sessions                 opens a session, returns its code and token
+//	GET  /sessions/{code}          returns the status of the session
+//	POST /sessions/{code}/shares   submits a share: {"share": "..."}
+//	GET  /sessions/{code}/message  reveals the message to the owner, with the
+//	                               header "Authorization: Bearer <token>"
+//	DELETE /sessions/{code}/shares/{index}
+//	                               drops a share, only for the owner
+//
+// Errors are returned as {"error": "..."} with the matching status code. The
+// route to open sessions is not authenticated, so it is only served by the
+// servers created with New, which should only listen on trusted addresses.
+// It implements the http.Handler interface.
+type Server struct {
+	conf     *gosss.Config
+	timeout  time.Duration
+	mtx      sync.Mutex
+	sessions map[string]*session
+	mux      *http.ServeMux
+}
+
+// New returns a Server that recovers the messages with the configuration
+// provided, which can be nil to take the prime number from the shares, and
+// wipes every session after the timeout provided. It serves the route to open
+// sessions, which is not authenticated, up to a maximum number of sessions
+// open at the same time.
+func New(conf *gosss.Config, timeout time.Duration) *Server {
+	s := NewClosed(conf, timeout)
+	s.mux.HandleFunc("POST /sessions", s.handleOpen)
+	return s
+}
+
+// NewClosed returns a Server like New does, but it does not serve the route
+// to open sessions, so they can only be opened with Open by the process that
+// runs the server. It should be used when the server listens on addresses
+// reachable by untrusted parties.
+func NewClosed(conf *gosss.Config, timeout time.Duration) *Server {
+	s := &Server{
+		conf:     conf,
+		timeout:  timeout,
+		sessions: map[string]*session{},
+		mux:      http.NewServeMux(),
+	}
+	s.mux.HandleFunc("GET /sessions/{code}", s.handleStatus)
+	s.mux.HandleFunc("POST /sessions/{code}/shares", s.handleSubmit)
+	s.mux.HandleFunc("GET /sessions/{code}/message", s.handleMessage)
+	s.mux.HandleFunc("DELETE /sessions/{code}/shares/{index}", s.handleDrop)
+	return s
+}
+
+// ServeHTTP serves the JSON API of the server.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Open opens a new recovery session that expires after the timeout of the
+// server. It returns the public information of the session, whose code must
+// be sent to the holders, and the token of the owner, which must be kept
+// secret to reveal the message. It returns an error if the maximum number of
+// sessions are already open or if the random code or token cannot be
+// generated.
+func (s *Server) Open() (Session, string, error) {
+	code, err := randomHex(codeLen)
+	if err != nil {
+		return Session{}, "", err
+	}
+	token, err := randomHex(tokenLen)
+	if err != nil {
+		return Session{}, "", err
+	}
+	sess := &session{
+		code:      code,
+		token:     token,
+		expiresAt: time.Now().Add(s.timeout),
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if len(s.sessions) >= maxSessions {
+		return Session{}, "", ErrTooManySessions
+	}
+	sess.timer = time.AfterFunc(s.timeout, func() { s.wipe(code) })
+	s.sessions[code] = sess
+	return sess.info(), token, nil
+}
+
+// Status returns the public information of the session with the code
+// provided. It returns an error if the session does not exist or has expired.
+func (s *Server) Status(code string) (Session, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	sess, ok := s.sessions[code]
+	if !ok {
+		return Session{}, ErrSessionNotFound
+	}
+	return sess.info(), nil
+}
+
+// Submit validates the share provided as string and adds it to the session
+// with the code provided. The share must be decoded, must not be a legacy
+// share, because its threshold is unknown, must have an index not submitted
+// before and must belong to the same set of the first share of the session.
+// The shares are accepted after the threshold is met, because the extra
+// shares are used to correct the wrong ones, up to a fixed limit per session
+// instead of the total number of shares of the set, so the shares of enrolled
+// holders are accepted too. It returns the public information of the session
+// after accepting the share or an error if the share is not valid or the
+// session does not accept more shares.
+func (s *Server) Submit(code, input string) (Session, error) {
+	var share gosss.Share
+	if err := share.UnmarshalText([]byte(input)); err != nil {
+		return Session{}, err
+	}
+	if share.SetID == nil {
+		return Session{}, gosss.ErrLegacyShare
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	sess, ok := s.sessions[code]
+	if !ok {
+		return Session{}, ErrSessionNotFound
+	}
+	if len(sess.shares) >= maxSessionShares {
+		return Session{}, ErrSessionComplete
+	}
+	for _, prev := range sess.shares {
+		if prev.Index.Cmp(share.Index) == 0 {
+			return Session{}, gosss.ErrDuplicatedShare
+		}
+	}
+	if len(sess.shares) > 0 {
+		first := sess.shares[0]
+		if !bytes.Equal(first.SetID, share.SetID) || !bytes.Equal(first.PrimeID, share.PrimeID) ||
+			first.Threshold != share.Threshold || first.Total != share.Total {
+			return Session{}, gosss.ErrShareSetMismatch
+		}
+		if first.Epoch != share.Epoch {
+			return Session{}, gosss.ErrEpochMismatch
+		}
+	}
+	sess.inputs = append(sess.inputs, strings.TrimSpace(input))
+	sess.shares = append(sess.shares, share)
+	return sess.info(), nil
+}
+
+// Reveal recovers the message of the session with the code provided with
+// RecoverMessageRobust, which corrects up to (n - k) / 2 wrong shares, where
+// n is the number of shares accepted and k the threshold, if the token
+// provided is the token of its owner and there are enough shares. Once the
+// message is recovered, the session is wiped, so it is revealed only once.
+// If it cannot be recovered, the session is kept, so the owner can drop the
+// wrong shares or wait for more of them. It returns an error if the session
+// does not exist, the token is not valid, there are not enough shares or the
+// message cannot be recovered.
+func (s *Server) Reveal(code, token string) ([]byte, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	sess, err := s.ownedSession(code, token)
+	if err != nil {
+		return nil, err
+	}
+	if !sess.info().Complete {
+		return nil, ErrSessionIncomplete
+	}
+	var conf *gosss.Config
+	if s.conf != nil {
+		copied := *s.conf
+		conf = &copied
+	}
+	message, _, err := gosss.RecoverMessageRobust(sess.inputs, conf)
+	if err != nil {
+		return nil, err
+	}
+	s.wipeLocked(code)
+	return message, nil
+}
+
+// Drop removes the share with the index provided from the session with the
+// code provided, if the token provided is the token of its owner, so a wrong
+// share can be replaced. If every share is dropped, the next share accepted
+// defines the set of the session again. It returns the public information of
+// the session after dropping the share or an error if the session does not
+// exist, the token is not valid or there is no share with the index provided.
+func (s *Server) Drop(code, token, index string) (Session, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	sess, err := s.ownedSession(code, token)
+	if err != nil {
+		return Session{}, err
+	}
+	for i, share := range sess.shares {
+		if share.Index.String() == index {
+			sess.inputs = slices.Delete(sess.inputs, i, i+1)
+			sess.shares = slices.Delete(sess.shares, i, i+1)
+			return sess.info(), nil
+		}
+	}
+	return Session{}, ErrShareNotFound
+}
+
+// ownedSession returns the session with the code provided if the token
+// provided is the token of its owner. The mutex of the server must be
+// locked. It returns an error if the session does not exist or the token is
+// not valid.
+func (s *Server) ownedSession(code, token string) (*session, error) {
+	sess, ok := s.sessions[code]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(sess.token)) != 1 {
+		return nil, ErrUnauthorized
+	}
+	return sess, nil
+}
+
+// wipe removes the session with the code provided and its shares.
+func (s *Server) wipe(code string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.wipeLocked(code)
+}
+
+// wipeLocked removes the session with the code provided and its shares. The
+// mutex of the server must be locked.
+func (s *Server) wipeLocked(code string) {
+	sess, ok := s.sessions[code]
+	if !ok {
+		return
+	}
+	sess.timer.Stop()
+	for i := range sess.inputs {
+		sess.inputs[i] = ""
+	}
+	sess.inputs, sess.shares = nil, nil
+	delete(s.sessions, code)
+}
+
+// handleOpen opens a new session and returns its information and the token of
+// its owner.
+func (s *Server) handleOpen(w http.ResponseWriter, r *http.Request) {
+	info, token, err := s.Open()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, struct {
+		Session
+		OwnerToken string `json:"ownerToken"`
+	}{info, token})
+}
+
+// handleStatus returns the information of the session.
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	info, err := s.Status(r.PathValue("code"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+// handleSubmit decodes the share of the request and submits it to the
+// session.
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Share string `json:"share"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyLen)).Decode(&req); err != nil || req.Share == "" {
+		writeError(w, ErrInvalidRequest)
+		return
+	}
+	info, err := s.Submit(r.PathValue("code"), req.Share)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+// handleDrop drops a share of the session for its owner, whose token is read
+// from the Authorization header.
+func (s *Server) handleDrop(w http.ResponseWriter, r *http.Request) {
+	info, err := s.Drop(r.PathValue("code"), bearerToken(r), r.PathValue("index"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+// handleMessage reveals the message of the session to its owner, whose token
+// is read from the Authorization header.
+func (s *Server) handleMessage(w http.ResponseWriter, r *http.Request) {
+	message, err := s.Reveal(r.PathValue("code"), bearerToken(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, struct {
+		Message []byte `json:"message"`
+	}{message})
+}
+
+// bearerToken returns the token of the Authorization header of the request.
+func bearerToken(r *http.Request) string {
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return token
+}
+
+// writeJSON writes the value provided encoded as JSON with the status code
+// provided.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes the error provided as JSON with the status code that
+// matches it.
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, statusCode(err), struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
+
+// statusCode returns the HTTP status code that matches the error provided.
+func statusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrSessionNotFound), errors.Is(err, ErrShareNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrUnauthorized):
+		return http.StatusForbidden
+	case errors.Is(err, ErrSessionIncomplete), errors.Is(err, ErrSessionComplete),
+		errors.Is(err, gosss.ErrDuplicatedShare), errors.Is(err, gosss.ErrShareSetMismatch),
+		errors.Is(err, gosss.ErrEpochMismatch):
+		return http.StatusConflict
+	case errors.Is(err, ErrTooManySessions):
+		return http.StatusTooManyRequests
+	case errors.Is(err, gosss.ErrReadingRandom):
+		return http.StatusInternalServerError
+	case errors.Is(err, gosss.ErrDecodingMessage), errors.Is(err, gosss.ErrPrimeMismatch),
+		errors.Is(err, gosss.ErrTooManyFaultyShares):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// randomHex returns n random bytes encoded in hexadecimal.
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Join(gosss.ErrReadingRandom, err)
+	}
+	return hex.EncodeToString(b), nil
+}
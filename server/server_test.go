@@ -0,0 +1,303 @@
+package server
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucasmenendez/gosss"
+)
+
+// request sends a request to the server provided and decodes the JSON
+// response into the value provided, returning the status code.
+func request(t *testing.T, ts *httptest.Server, method, path, token, body string, v any) int {
+	t.Helper()
+	req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	res, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer res.Body.Close()
+	if v != nil {
+		if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return res.StatusCode
+}
+
+func TestServerRecovery(t *testing.T) {
+	message := []byte("message collected over http")
+	shares, err := gosss.HideMessage(message, &gosss.Config{Shares: 5, Min: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	other, err := gosss.HideMessage(message, &gosss.Config{Shares: 5, Min: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ts := httptest.NewServer(New(nil, time.Minute))
+	defer ts.Close()
+
+	var opened struct {
+		Session
+		OwnerToken string `json:"ownerToken"`
+	}
+	if status := request(t, ts, http.MethodPost, "/sessions", "", "", &opened); status != http.StatusCreated {
+		t.Fatalf("expected %d, got %d", http.StatusCreated, status)
+	}
+	if opened.Code == "" || opened.OwnerToken == "" {
+		t.Fatalf("expected session code and owner token, got %+v", opened)
+	}
+	path := "/sessions/" + opened.Code
+	submit := func(share string, expected int) Session {
+		t.Helper()
+		var info Session
+		body, _ := json.Marshal(map[string]string{"share": share})
+		if status := request(t, ts, http.MethodPost, path+"/shares", "", string(body), nil); status != expected {
+			t.Fatalf("expected %d, got %d", expected, status)
+		}
+		request(t, ts, http.MethodGet, path, "", "", &info)
+		return info
+	}
+	info := submit(shares[0], http.StatusOK)
+	if info.Accepted != 1 || info.Required != 3 || info.Complete {
+		t.Errorf("unexpected status: %+v", info)
+	}
+	// invalid, duplicated and mismatched shares are rejected
+	submit("invalid", http.StatusBadRequest)
+	submit(shares[0], http.StatusConflict)
+	submit(other[1], http.StatusConflict)
+	// the message is not revealed before the threshold is met
+	if status := request(t, ts, http.MethodGet, path+"/message", opened.OwnerToken, "", nil); status != http.StatusConflict {
+		t.Errorf("expected %d, got %d", http.StatusConflict, status)
+	}
+	submit(shares[2], http.StatusOK)
+	info = submit(shares[4], http.StatusOK)
+	if info.Accepted != 3 || !info.Complete {
+		t.Errorf("unexpected status: %+v", info)
+	}
+	// the extra shares are accepted until the session has every share
+	submit(shares[3], http.StatusOK)
+	info = submit(shares[1], http.StatusOK)
+	if info.Accepted != 5 || len(info.Indexes) != 5 {
+		t.Errorf("unexpected status: %+v", info)
+	}
+	// only the owner can drop shares
+	if status := request(t, ts, http.MethodDelete, path+"/shares/2", "", "", nil); status != http.StatusForbidden {
+		t.Errorf("expected %d, got %d", http.StatusForbidden, status)
+	}
+	if status := request(t, ts, http.MethodDelete, path+"/shares/2", opened.OwnerToken, "", &info); status != http.StatusOK {
+		t.Errorf("expected %d, got %d", http.StatusOK, status)
+	}
+	if info.Accepted != 4 {
+		t.Errorf("unexpected status: %+v", info)
+	}
+	// the message is only revealed to the owner
+	if status := request(t, ts, http.MethodGet, path+"/message", "", "", nil); status != http.StatusForbidden {
+		t.Errorf("expected %d, got %d", http.StatusForbidden, status)
+	}
+	if status := request(t, ts, http.MethodGet, path+"/message", opened.Code, "", nil); status != http.StatusForbidden {
+		t.Errorf("expected %d, got %d", http.StatusForbidden, status)
+	}
+	var revealed struct {
+		Message []byte `json:"message"`
+	}
+	if status := request(t, ts, http.MethodGet, path+"/message", opened.OwnerToken, "", &revealed); status != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, status)
+	}
+	if string(revealed.Message) != string(message) {
+		t.Errorf("expected %s, got %s", message, revealed.Message)
+	}
+	// the session is wiped once the message is revealed
+	if status := request(t, ts, http.MethodGet, path+"/message", opened.OwnerToken, "", nil); status != http.StatusNotFound {
+		t.Errorf("expected %d, got %d", http.StatusNotFound, status)
+	}
+}
+
+func TestServerTimeout(t *testing.T) {
+	shares, err := gosss.HideMessage([]byte("expired"), &gosss.Config{Shares: 3, Min: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	srv := New(nil, 50*time.Millisecond)
+	info, _, err := srv.Open()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := srv.Submit(info.Code, shares[0]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if _, err := srv.Status(info.Code); err != ErrSessionNotFound {
+		t.Errorf("expected %v, got %v", ErrSessionNotFound, err)
+	}
+	if _, err := srv.Submit(info.Code, shares[1]); err != ErrSessionNotFound {
+		t.Errorf("expected %v, got %v", ErrSessionNotFound, err)
+	}
+}
+
+func TestServerLegacyShare(t *testing.T) {
+	srv := New(nil, time.Minute)
+	info, _, err := srv.Open()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	shares, err := gosss.HideMessage([]byte("legacy"), &gosss.Config{Shares: 3, Min: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var share gosss.Share
+	if err := share.UnmarshalText([]byte(shares[0])); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	share.SetID = nil
+	legacy, err := share.MarshalText()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := srv.Submit(info.Code, string(legacy)); err != gosss.ErrLegacyShare {
+		t.Errorf("expected %v, got %v", gosss.ErrLegacyShare, err)
+	}
+}
+
+// forgeShare returns the share provided with a wrong value, encoded with its
+// text representation.
+func forgeShare(t *testing.T, input string) string {
+	t.Helper()
+	var share gosss.Share
+	if err := share.UnmarshalText([]byte(input)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	share.Values[0].Add(share.Values[0], big.NewInt(1))
+	return share.String()
+}
+
+func TestServerWrongShares(t *testing.T) {
+	message := []byte("message with wrong shares")
+	shares, err := gosss.HideMessage(message, &gosss.Config{Shares: 5, Min: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	other, err := gosss.HideMessage(message, &gosss.Config{Shares: 5, Min: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	srv := New(nil, time.Minute)
+	info, token, err := srv.Open()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// a share of another set submitted first is dropped by the owner, so the
+	// shares of the right set can be submitted
+	if _, err := srv.Submit(info.Code, other[0]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := srv.Submit(info.Code, shares[1]); err != gosss.ErrShareSetMismatch {
+		t.Errorf("expected %v, got %v", gosss.ErrShareSetMismatch, err)
+	}
+	if _, err := srv.Drop(info.Code, "wrong", "1"); err != ErrUnauthorized {
+		t.Errorf("expected %v, got %v", ErrUnauthorized, err)
+	}
+	if _, err := srv.Drop(info.Code, token, "7"); err != ErrShareNotFound {
+		t.Errorf("expected %v, got %v", ErrShareNotFound, err)
+	}
+	if info, err = srv.Drop(info.Code, token, "1"); err != nil || info.Accepted != 0 {
+		t.Fatalf("unexpected result: %+v %v", info, err)
+	}
+	// two forged shares of four can not be corrected, but the session is kept
+	// so the owner can drop them
+	for _, input := range []string{forgeShare(t, shares[0]), forgeShare(t, shares[1]), shares[2], shares[3]} {
+		if _, err := srv.Submit(info.Code, input); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := srv.Reveal(info.Code, token); err != gosss.ErrTooManyFaultyShares {
+		t.Errorf("expected %v, got %v", gosss.ErrTooManyFaultyShares, err)
+	}
+	if _, err := srv.Drop(info.Code, token, "2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// a single forged share of five is corrected
+	for _, input := range []string{shares[1], shares[4]} {
+		if _, err := srv.Submit(info.Code, input); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	recovered, err := srv.Reveal(info.Code, token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(recovered) != string(message) {
+		t.Errorf("expected %s, got %s", message, recovered)
+	}
+}
+
+func TestServerEnrolledShare(t *testing.T) {
+	message := []byte("message with an enrolled holder")
+	inputs, err := gosss.HideMessage(message, &gosss.Config{Shares: 3, Min: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	shares := make([]gosss.Share, len(inputs))
+	for i, input := range inputs {
+		if err := shares[i].UnmarshalText([]byte(input)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	enrolled, err := gosss.EnrollShares(shares[:2], big.NewInt(4), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the share of the enrolled holder is accepted after the total number of
+	// shares of the set
+	srv := New(nil, time.Minute)
+	info, token, err := srv.Open()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, input := range append(inputs, enrolled.String()) {
+		if info, err = srv.Submit(info.Code, input); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if info.Accepted != 4 {
+		t.Errorf("unexpected status: %+v", info)
+	}
+	recovered, err := srv.Reveal(info.Code, token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(recovered) != string(message) {
+		t.Errorf("expected %s, got %s", message, recovered)
+	}
+}
+
+func TestServerOpenSessions(t *testing.T) {
+	// the closed server does not serve the route to open sessions
+	closed := httptest.NewServer(NewClosed(nil, time.Minute))
+	defer closed.Close()
+	if status := request(t, closed, http.MethodPost, "/sessions", "", "", nil); status == http.StatusCreated {
+		t.Errorf("unexpected status %d", status)
+	}
+	// the number of sessions open at the same time is bounded
+	srv := New(nil, time.Minute)
+	for range maxSessions {
+		if _, _, err := srv.Open(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, _, err := srv.Open(); err != ErrTooManySessions {
+		t.Errorf("expected %v, got %v", ErrTooManySessions, err)
+	}
+}
@@ -0,0 +1,185 @@
+package gosss
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+)
+
+// Share struct represents a share of a message generated with the Shamir Secret
+// Sharing algorithm. It includes the index of the holder, which is the x
+// coordinate of its points, and the values of the share, which are the y
+// coordinates of the point of every chunk of the message. It also includes the
+// metadata of the set of shares it belongs to: the set identifier, the
+// identifier of the prime number and its registered name, if it was selected by
+// name, the minimum number of shares to recover the message, the total number
+// of shares and the epoch of the share, which is incremented every time the
+// shares are refreshed. Shares decoded from the legacy format have no metadata,
+// so the set and prime identifiers are nil and the threshold, total and epoch
+// are zero, and a single value with the message encoded without length prefix.
+// Blinding shares, generated by HidePedersenMessage to verify the shares, are
+// marked so they can not be used to recover the message. It implements the
+// encoding.BinaryMarshaler, encoding.TextMarshaler and json.Marshaler
+// interfaces, and their unmarshaler counterparts, so it can be stored and
+// transmitted without handling its encoding.
+type Share struct {
+	Index     *big.Int
+	Values    []*big.Int
+	SetID     []byte
+	PrimeID   []byte
+	PrimeName string
+	Threshold int
+	Total     int
+	Epoch     int
+	Blinding  bool
+}
+
+// jsonShare struct is the JSON representation of a Share. The values and the
+// identifiers are encoded as hexadecimal strings.
+type jsonShare struct {
+	Index     *big.Int `json:"index"`
+	Values    []string `json:"values"`
+	SetID     string   `json:"setId,omitempty"`
+	PrimeID   string   `json:"primeId,omitempty"`
+	PrimeName string   `json:"primeName,omitempty"`
+	Threshold int      `json:"threshold,omitempty"`
+	Total     int      `json:"total,omitempty"`
+	Epoch     int      `json:"epoch,omitempty"`
+	Blinding  bool     `json:"blinding,omitempty"`
+}
+
+// legacy returns true if the share was decoded from the legacy format, so it
+// does not include any metadata.
+func (s *Share) legacy() bool {
+	return s.SetID == nil
+}
+
+// MarshalBinary encodes the share using the versioned binary format, or the
+// legacy format if the share does not include metadata. It implements the
+// encoding.BinaryMarshaler interface.
+func (s Share) MarshalBinary() ([]byte, error) {
+	return marshalShare(&s)
+}
+
+// UnmarshalBinary decodes a share encoded in binary, using the versioned or
+// the legacy format. It implements the encoding.BinaryUnmarshaler interface.
+func (s *Share) UnmarshalBinary(data []byte) error {
+	decoded, err := unmarshalShare(data)
+	if err != nil {
+		return err
+	}
+	*s = *decoded
+	return nil
+}
+
+// MarshalText encodes the share as the hexadecimal representation of its
+// binary encoding. It implements the encoding.TextMarshaler interface.
+func (s Share) MarshalText() ([]byte, error) {
+	b, err := s.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	text := make([]byte, hex.EncodedLen(len(b)))
+	hex.Encode(text, b)
+	return text, nil
+}
+
+// UnmarshalText decodes a share from the hexadecimal representation of its
+// binary encoding, or from its mnemonic if it has more than one word. It
+// implements the encoding.TextUnmarshaler interface.
+func (s *Share) UnmarshalText(text []byte) error {
+	b, err := decodeShareText(string(text))
+	if err != nil {
+		return err
+	}
+	return s.UnmarshalBinary(b)
+}
+
+// Mnemonic returns the share encoded as a sequence of words with
+// EncodeMnemonic from its binary encoding. It returns an error if the share
+// cannot be encoded.
+func (s Share) Mnemonic() (string, error) {
+	b, err := s.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+	return EncodeMnemonic(b), nil
+}
+
+// String returns the text representation of the share or an empty string if
+// it cannot be encoded.
+func (s Share) String() string {
+	text, err := s.MarshalText()
+	if err != nil {
+		return ""
+	}
+	return string(text)
+}
+
+// MarshalJSON encodes the share as a JSON object with its fields, encoding
+// the values and identifiers as hexadecimal strings. It implements the
+// json.Marshaler interface.
+func (s Share) MarshalJSON() ([]byte, error) {
+	if s.Index == nil {
+		return nil, ErrInvalidShare
+	}
+	js := jsonShare{
+		Index:     s.Index,
+		Values:    make([]string, len(s.Values)),
+		SetID:     hex.EncodeToString(s.SetID),
+		PrimeID:   hex.EncodeToString(s.PrimeID),
+		PrimeName: s.PrimeName,
+		Threshold: s.Threshold,
+		Total:     s.Total,
+		Epoch:     s.Epoch,
+		Blinding:  s.Blinding,
+	}
+	for i, value := range s.Values {
+		js.Values[i] = value.Text(16)
+	}
+	return json.Marshal(js)
+}
+
+// UnmarshalJSON decodes a share from a JSON object with its fields, or from a
+// JSON string with its text representation. It implements the
+// json.Unmarshaler interface.
+func (s *Share) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '"' {
+		var text string
+		if err := json.Unmarshal(trimmed, &text); err != nil {
+			return ErrInvalidShare
+		}
+		return s.UnmarshalText([]byte(text))
+	}
+	var js jsonShare
+	if err := json.Unmarshal(data, &js); err != nil || js.Index == nil {
+		return ErrInvalidShare
+	}
+	decoded := Share{
+		Index:     js.Index,
+		Values:    make([]*big.Int, len(js.Values)),
+		PrimeName: js.PrimeName,
+		Threshold: js.Threshold,
+		Total:     js.Total,
+		Epoch:     js.Epoch,
+		Blinding:  js.Blinding,
+	}
+	for i, value := range js.Values {
+		var ok bool
+		if decoded.Values[i], ok = new(big.Int).SetString(value, 16); !ok {
+			return ErrInvalidShare
+		}
+	}
+	var err error
+	if js.SetID != "" {
+		if decoded.SetID, err = hex.DecodeString(js.SetID); err != nil || len(decoded.SetID) != setIDLen {
+			return ErrInvalidShare
+		}
+		if decoded.PrimeID, err = hex.DecodeString(js.PrimeID); err != nil || len(decoded.PrimeID) != primeIDLen {
+			return ErrInvalidShare
+		}
+	}
+	*s = decoded
+	return nil
+}
@@ -0,0 +1,93 @@
+package gosss
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestShareMarshalText(t *testing.T) {
+	shares, err := HideMessageShares(examplePrivateMessage, &Config{Shares: 3, Min: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text, err := shares[0].MarshalText()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(text) != shares[0].String() {
+		t.Errorf("unexpected string: %s", shares[0].String())
+	}
+	var decoded Share
+	if err := decoded.UnmarshalText(text); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded.Index.Cmp(shares[0].Index) != 0 || !bytes.Equal(decoded.SetID, shares[0].SetID) {
+		t.Errorf("unexpected share: %v", decoded)
+	}
+	// legacy share
+	legacy, err := shareToStr(big.NewInt(1), big.NewInt(2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := decoded.UnmarshalText([]byte(legacy)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !decoded.legacy() || decoded.String() != legacy {
+		t.Errorf("unexpected share: %v", decoded)
+	}
+	// invalid shares
+	if err := decoded.UnmarshalText([]byte("zz")); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+	if _, err := (Share{}).MarshalText(); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestShareMarshalJSON(t *testing.T) {
+	config := &Config{Shares: 4, Min: 3}
+	shares, err := HideMessageShares(examplePrivateMessage, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	encoded, err := json.Marshal(shares)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded []Share
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	message, err := RecoverMessageShares(decoded[1:], config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(message, examplePrivateMessage) {
+		t.Errorf("unexpected message: %s", message)
+	}
+	// share encoded as a JSON string with its text representation
+	encoded, err = json.Marshal(shares[0].String())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var share Share
+	if err := json.Unmarshal(encoded, &share); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if share.Index.Cmp(shares[0].Index) != 0 {
+		t.Errorf("unexpected share: %v", share)
+	}
+	// invalid shares
+	for _, invalid := range []string{
+		`{}`,
+		`{"index":1,"values":["zz"]}`,
+		`{"index":1,"values":["01"],"setId":"01"}`,
+		`"zz"`,
+	} {
+		if err := json.Unmarshal([]byte(invalid), &share); err == nil {
+			t.Errorf("expected error for %s, got nil", invalid)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package gosss
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+)
+
+// ShortShare struct represents a share generated with HideShort. It includes
+// the share of the encryption key, generated with the Shamir Secret Sharing
+// algorithm, and the fragment of the encrypted data, generated with the
+// Rabin's information dispersal algorithm. Both have the same index and
+// metadata. It implements the encoding.BinaryMarshaler and
+// encoding.TextMarshaler interfaces, and their unmarshaler counterparts.
+type ShortShare struct {
+	Key      Share
+	Fragment Share
+}
+
+// MarshalBinary encodes the share as the binary encoding of the key share,
+// prefixed by its length encoded as a varint, followed by the binary encoding
+// of the fragment. It implements the encoding.BinaryMarshaler interface.
+func (s ShortShare) MarshalBinary() ([]byte, error) {
+	key, err := s.Key.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	fragment, err := s.Fragment.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	b := binary.AppendUvarint(nil, uint64(len(key)))
+	b = append(b, key...)
+	return append(b, fragment...), nil
+}
+
+// UnmarshalBinary decodes a share encoded with MarshalBinary. It implements
+// the encoding.BinaryUnmarshaler interface.
+func (s *ShortShare) UnmarshalBinary(data []byte) error {
+	r := &shareReader{b: data}
+	key := r.bytes()
+	if r.err {
+		return ErrInvalidShare
+	}
+	decoded := ShortShare{}
+	if err := decoded.Key.UnmarshalBinary(key); err != nil {
+		return err
+	}
+	if err := decoded.Fragment.UnmarshalBinary(r.b); err != nil {
+		return err
+	}
+	*s = decoded
+	return nil
+}
+
+// MarshalText encodes the share as the hexadecimal representation of its
+// binary encoding. It implements the encoding.TextMarshaler interface.
+func (s ShortShare) MarshalText() ([]byte, error) {
+	b, err := s.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	text := make([]byte, hex.EncodedLen(len(b)))
+	hex.Encode(text, b)
+	return text, nil
+}
+
+// UnmarshalText decodes a share from the hexadecimal representation of its
+// binary encoding. It implements the encoding.TextUnmarshaler interface.
+func (s *ShortShare) UnmarshalText(text []byte) error {
+	b := make([]byte, hex.DecodedLen(len(text)))
+	if _, err := hex.Decode(b, text); err != nil {
+		return ErrInvalidShare
+	}
+	return s.UnmarshalBinary(b)
+}
+
+// String returns the text representation of the share or an empty string if
+// it cannot be encoded.
+func (s ShortShare) String() string {
+	text, err := s.MarshalText()
+	if err != nil {
+		return ""
+	}
+	return string(text)
+}
+
+// HideShort hides the data provided with the "secret sharing made short"
+// scheme of Krawczyk, so every share is about the size of the data divided
+// by the threshold, instead of the size of the data. It encrypts the data
+// with a fresh AES-256-GCM key, hides the key with the Shamir Secret Sharing
+// algorithm and splits the ciphertext with the Rabin's information dispersal
+// algorithm in the finite field of the configuration: the ciphertext is
+// encoded as field elements, which are grouped in blocks of k elements, where
+// k is the threshold, and every block is considered as the points of a
+// polynomial of degree k - 1 at the fixed x coordinates -1, ..., -k. Every
+// fragment includes the evaluation of the polynomial of every block at the
+// index of the share, so any k fragments can recover the ciphertext. The
+// fragments do not hide the ciphertext, its confidentiality relies on the
+// encryption. It returns the shares as strings, encoded with their text
+// representation, or an error if the configuration is not valid or the data
+// cannot be encrypted.
+func HideShort(data []byte, conf *Config) ([]string, error) {
+	// the prime number is selected for the key, and it is also used to
+	// disperse the ciphertext
+	conf, err := messageConfig(make([]byte, blobKeyLen), conf)
+	if err != nil {
+		return nil, err
+	}
+	key, keyShares, err := hideBlobKey(conf)
+	if err != nil {
+		return nil, err
+	}
+	setID := keyShares[0].SetID
+	nonce, ciphertext, err := encryptBlob(key, setID, data)
+	if err != nil {
+		return nil, err
+	}
+	// encode the nonce and the ciphertext as field elements, padded with
+	// zeros to a multiple of the threshold
+	elements := messageToChunks(append(nonce, ciphertext...), conf.MaxMessageLen())
+	for len(elements)%conf.Min != 0 {
+		elements = append(elements, big.NewInt(0))
+	}
+	// disperse every block of elements evaluating its polynomial at the index
+	// of every share
+	dataXs := dispersalPoints(conf.Min, conf.Prime)
+	strShares := make([]string, len(keyShares))
+	for i, keyShare := range keyShares {
+		fragment := keyShare
+		fragment.Values = make([]*big.Int, 0, len(elements)/conf.Min)
+		for start := 0; start < len(elements); start += conf.Min {
+			block := elements[start : start+conf.Min]
+			y := lagrangeInterpolation(dataXs, block, conf.Prime, keyShare.Index)
+			fragment.Values = append(fragment.Values, y)
+		}
+		text, err := ShortShare{Key: keyShare, Fragment: fragment}.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		strShares[i] = string(text)
+	}
+	return strShares, nil
+}
+
+// RecoverShort recovers the data hidden with HideShort from the shares
+// provided as strings. It recovers the encryption key with
+// RecoverMessageShares, so it returns the same errors, and the ciphertext
+// from the fragments of the first k shares, where k is the threshold, by
+// interpolating the polynomial of every block at the fixed x coordinates
+// -1, ..., -k. It returns an error if any share cannot be decoded, with its
+// position in the input, if the key shares and the fragments do not match or
+// if the data cannot be authenticated, because some share is wrong.
+func RecoverShort(inputs []string, conf *Config) ([]byte, error) {
+	keyShares := make([]Share, len(inputs))
+	fragments := make([]Share, len(inputs))
+	for i, input := range inputs {
+		var share ShortShare
+		if err := share.UnmarshalText([]byte(input)); err != nil {
+			return nil, &ShareError{Position: i, Err: err}
+		}
+		if share.Key.Index == nil || share.Fragment.Index == nil ||
+			share.Key.Index.Cmp(share.Fragment.Index) != 0 {
+			return nil, &ShareError{Position: i, Err: ErrInvalidShare}
+		}
+		if !bytes.Equal(share.Key.SetID, share.Fragment.SetID) {
+			return nil, &ShareError{Position: i, Err: ErrShareSetMismatch}
+		}
+		keyShares[i], fragments[i] = share.Key, share.Fragment
+	}
+	key, err := recoverBlobKey(keyShares, conf)
+	if err != nil {
+		return nil, err
+	}
+	// check the fragments like the key shares and recover the elements of
+	// every block from the first k of them
+	conf = sharesConfig(fragments, conf)
+	if err := checkShares(fragments, conf); err != nil {
+		return nil, err
+	}
+	k := fragments[0].Threshold
+	if k < MinMinShares || len(fragments) < k {
+		return nil, ErrNotEnoughShares
+	}
+	xs, blocksYs := sharesPoints(fragments[:k])
+	dataXs := dispersalPoints(k, conf.Prime)
+	elements := make([]*big.Int, 0, len(blocksYs)*k)
+	for _, ys := range blocksYs {
+		for _, x := range dataXs {
+			elements = append(elements, lagrangeInterpolation(xs, ys, conf.Prime, x))
+		}
+	}
+	encrypted, err := chunksToMessage(elements, conf.MaxMessageLen())
+	if err != nil || len(encrypted) < envelopeNonceLen {
+		return nil, ErrBlobAuthentication
+	}
+	nonce, ciphertext := encrypted[:envelopeNonceLen], encrypted[envelopeNonceLen:]
+	return decryptBlob(key, keyShares[0].SetID, nonce, ciphertext)
+}
+
+// dispersalPoints returns the x coordinates of the elements of every block of
+// the information dispersal, which are -1, ..., -k in the finite field, so
+// they never match the index of a share.
+func dispersalPoints(k int, prime *big.Int) []*big.Int {
+	xs := make([]*big.Int, k)
+	for i := range xs {
+		xs[i] = new(big.Int).Sub(prime, big.NewInt(int64(i+1)))
+	}
+	return xs
+}
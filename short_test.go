@@ -0,0 +1,60 @@
+package gosss
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestHideRecoverShort(t *testing.T) {
+	data := make([]byte, 64<<10)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	config := &Config{
+		Shares: 6,
+		Min:    4,
+	}
+	shares, err := HideShort(data, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// every share is about the size of the data divided by the threshold,
+	// encoded in hexadecimal
+	for _, share := range shares {
+		if size := len(share) / 2; size > len(data)/config.Min+len(data)/10 {
+			t.Errorf("unexpected share size: %d", size)
+		}
+	}
+	recovered, err := RecoverShort([]string{shares[5], shares[1], shares[3], shares[0]}, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(recovered, data) {
+		t.Errorf("unexpected data recovered")
+	}
+	// the shares can be recovered without configuration
+	if recovered, err = RecoverShort(shares[2:], nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(recovered, data) {
+		t.Errorf("unexpected data recovered")
+	}
+	if _, err := RecoverShort(shares[:3], config); err != ErrNotEnoughShares {
+		t.Errorf("expected %v, got %v", ErrNotEnoughShares, err)
+	}
+	// a modified fragment is detected
+	var share ShortShare
+	if err := share.UnmarshalText([]byte(shares[0])); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	share.Fragment.Values[3].Add(share.Fragment.Values[3], big.NewInt(1))
+	modified := append([]string{share.String()}, shares[1:4]...)
+	if _, err := RecoverShort(modified, config); err != ErrBlobAuthentication {
+		t.Errorf("expected %v, got %v", ErrBlobAuthentication, err)
+	}
+	if _, err := RecoverShort([]string{"zz"}, config); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
@@ -1,79 +1,246 @@
 package gosss
 
-import "math/big"
+import (
+	"bytes"
+	"math/big"
+	"slices"
+)
 
 // HideMessage generates the shares of the message using the Shamir Secret
-// Sharing algorithm. It returns the shares as strings. The message is encoded
-// as a big.Int and the shares are calculated solving a polynomial with random
-// coefficients. The first coefficient is the encoded message. It uses the
-// configuration provided in the Config struct, if the prime number is not
-// defined it uses the 12th Mersenne Prime (2^127 - 1) as default. It returns
-// an error if the message cannot be encoded.
+// Sharing algorithm. It returns the shares as strings, encoded with their text
+// representation, or as mnemonics if the configuration uses the mnemonic
+// encoding. It uses HideMessageShares to generate the shares, so it returns
+// the same errors. If the configuration uses the GF(2^8) backend or defines a
+// field, the shares are the bytes of every share, encoded as hexadecimal
+// strings or as mnemonics.
 func HideMessage(message []byte, conf *Config) ([]string, error) {
-	// the hide operation needs the minimum number of shares and the total
-	// number of shares, so if the configuration is not provided, return an
-	// error
-	if conf == nil {
-		return nil, ErrRequiredConfig
+	if conf != nil && conf.Field != nil {
+		return hideMessageField(message, conf)
 	}
-	conf.prepare()
-	// validate the configuration for the message provided
-	if err := conf.ValidConfig(message); err != nil {
-		return nil, err
+	if conf != nil && conf.Backend == GF256Backend {
+		return hideMessageGF256(message, conf)
 	}
-	// calculate k random coefficients for the polynomial, where k is the
-	// minimum number of shares less one (the secret is the first coefficient)
-	coeffs, err := calcCoeffs(new(big.Int).SetBytes(message), conf.Prime, conf.Min)
+	shares, err := HideMessageShares(message, conf)
 	if err != nil {
 		return nil, err
 	}
-	// calculate the shares with the polynomial and the prime number
-	xs, ys := calcShares(coeffs, conf.Shares, conf.Prime)
-	// encode the shares
-	shares := []string{}
-	for i := 0; i < len(xs); i++ {
-		share, err := shareToStr(xs[i], ys[i])
+	strShares := make([]string, 0, len(shares))
+	for _, share := range shares {
+		b, err := share.MarshalBinary()
 		if err != nil {
 			return nil, err
 		}
-		shares = append(shares, share)
+		strShares = append(strShares, encodeShareText(b, conf.Encoding))
 	}
-	return shares, nil
+	return strShares, nil
 }
 
 // RecoverMessage recovers the message from the shares using the Shamir Secret
-// Sharing algorithm. It returns the message as a string. The shares are given
-// as strings. It uses the configuration provided in the Config struct, if the
-// prime number is not defined it uses the 12th Mersenne Prime (2^127 - 1) as
+// Sharing algorithm. The shares are given as strings, encoded with their text
+// representation or as mnemonics, which can be mixed. It decodes the shares
+// and uses RecoverMessageShares to recover the message, so it returns the same
+// errors. If any share cannot be decoded, for example because its checksum
+// does not match, it returns a ShareError with the position of the share in
+// the input. If the configuration uses the GF(2^8) backend or defines a field,
+// the shares are decoded as the bytes of every share, encoded as hexadecimal
+// strings or as mnemonics.
+func RecoverMessage(inputs []string, conf *Config) ([]byte, error) {
+	if conf != nil && conf.Field != nil {
+		return recoverMessageField(inputs, conf)
+	}
+	if conf != nil && conf.Backend == GF256Backend {
+		return recoverMessageGF256(inputs, conf)
+	}
+	shares := make([]Share, len(inputs))
+	for i, input := range inputs {
+		if err := shares[i].UnmarshalText([]byte(input)); err != nil {
+			return nil, &ShareError{Position: i, Err: err}
+		}
+	}
+	return RecoverMessageShares(shares, conf)
+}
+
+// HideMessageShares generates the shares of the message using the Shamir
+// Secret Sharing algorithm. The message is split into chunks that fit in the
+// prime number, each chunk is encoded as a big.Int and its shares are
+// calculated solving a polynomial with random coefficients. The first
+// coefficient is the encoded chunk. Every holder receives a single share that
+// includes the points of every chunk for the same x coordinate and the
+// metadata of the set of shares. It uses the configuration provided in the
+// Config struct, if the prime number is not defined it uses the bn254 𝔽r
+// prime as default. It returns an error if the message cannot be encoded.
+func HideMessageShares(message []byte, conf *Config) ([]Share, error) {
+	// the hide operation needs the minimum number of shares and the total
+	// number of shares, so if the configuration is not provided, return an
+	// error, and selects the prime number for the message if it is required
+	conf, err := messageConfig(message, conf)
+	if err != nil {
+		return nil, err
+	}
+	shares, _, err := hideMessage(message, conf)
+	return shares, err
+}
+
+// hideMessage generates the shares of the message as HideMessageShares does,
+// but it also returns the coefficients of the polynomial of every chunk, so
+// they can be used to commit to the polynomials. The configuration must be
+// prepared with messageConfig.
+func hideMessage(message []byte, conf *Config) ([]Share, [][]*big.Int, error) {
+	// validate the configuration for the message provided
+	if err := conf.ValidConfig(message); err != nil {
+		return nil, nil, err
+	}
+	if conf.Backend != PrimeBackend || conf.Field != nil {
+		return nil, nil, ErrUnsupportedBackend
+	}
+	// split the message into chunks that fit in the prime number and
+	// calculate the y coordinates of the shares of every chunk, the x
+	// coordinates are the same for every chunk
+	var xs []*big.Int
+	chunks := messageToChunks(message, conf.MaxMessageLen())
+	chunksCoeffs := make([][]*big.Int, len(chunks))
+	chunksYs := make([][]*big.Int, len(chunks))
+	for i, chunk := range chunks {
+		// calculate k random coefficients for the polynomial, where k is the
+		// minimum number of shares less one (the chunk is the first
+		// coefficient)
+		coeffs, err := calcCoeffs(chunk, conf.Prime, conf.Min)
+		if err != nil {
+			return nil, nil, err
+		}
+		// calculate the shares with the polynomial and the prime number
+		chunksCoeffs[i] = coeffs
+		xs, chunksYs[i] = calcShares(coeffs, conf.Shares, conf.Prime)
+	}
+	// generate a random identifier for the set of shares
+	setID, err := newSetID()
+	if err != nil {
+		return nil, nil, err
+	}
+	// group the y coordinates of every chunk by the x coordinate of the
+	// holder
+	shares := make([]Share, len(xs))
+	for i := range xs {
+		shares[i] = Share{
+			Index:     xs[i],
+			Values:    make([]*big.Int, len(chunks)),
+			SetID:     setID,
+			PrimeID:   conf.primeID(),
+			PrimeName: conf.PrimeName,
+			Threshold: conf.Min,
+			Total:     conf.Shares,
+		}
+		for j := range chunks {
+			shares[i].Values[j] = chunksYs[j][i]
+		}
+	}
+	return shares, chunksCoeffs, nil
+}
+
+// RecoverMessageShares recovers the message from the shares using the Shamir
+// Secret Sharing algorithm. It uses the configuration provided in the Config
+// struct, if the prime number is not defined it uses the bn254 𝔽r prime as
 // default. It returns an error if the message cannot be recovered. The shares
 // include the index of the share and the share itself, so the order of the
-// provided shares does not matter. It decodes the points of the polynomial from
-// the shares and calculates the Lagrange interpolation to recover the secret.
-func RecoverMessage(inputs []string, conf *Config) ([]byte, error) {
+// provided shares does not matter. It takes the points of every chunk of the
+// message from the shares and calculates the Lagrange interpolation to
+// recover each chunk, then joins them into the original message.
+func RecoverMessageShares(shares []Share, conf *Config) ([]byte, error) {
 	// the recover operation does not need the minimum number of shares or the
-	// total number of shares, so if the configuration is not provided, create a
-	// empty configuration before prepare the it.
-	if conf == nil {
-		conf = &Config{}
-	}
-	// prepare the configuration to recover the message
-	conf.prepare()
+	// total number of shares, so if the configuration is not provided, use an
+	// empty configuration with the prime number named in the shares.
+	conf = sharesConfig(shares, conf)
 	if err := conf.ValidPrime(); err != nil {
 		return nil, err
 	}
-	// convert shares to big.Ints points coordinates
-	xs, ys := []*big.Int{}, []*big.Int{}
-	for _, input := range inputs {
-		x, y, err := strToShare(input)
-		if err != nil {
-			return nil, err
+	// check that the shares can be used together and that there are at least
+	// as many shares as the threshold of the set
+	if err := checkShares(shares, conf); err != nil {
+		return nil, err
+	}
+	if len(shares) < shares[0].Threshold {
+		return nil, ErrNotEnoughShares
+	}
+	// calculate every chunk using the Lagrange interpolation, the chunk is the
+	// first coefficient of its polynomial (x = 0)
+	xs, chunksYs := sharesPoints(shares)
+	chunks := make([]*big.Int, len(chunksYs))
+	for i, ys := range chunksYs {
+		chunks[i] = lagrangeInterpolation(xs, ys, conf.Prime, big.NewInt(0))
+	}
+	return decodeChunks(chunks, shares[0].legacy(), conf)
+}
+
+// sharesPoints converts the shares provided to the coordinates of the points
+// of every chunk. It returns the x coordinates of the shares and the y
+// coordinates of every chunk, in the same order of the shares.
+func sharesPoints(shares []Share) ([]*big.Int, [][]*big.Int) {
+	xs, chunksYs := []*big.Int{}, make([][]*big.Int, len(shares[0].Values))
+	for _, s := range shares {
+		xs = append(xs, s.Index)
+		for i, y := range s.Values {
+			chunksYs[i] = append(chunksYs[i], y)
+		}
+	}
+	return xs, chunksYs
+}
+
+// decodeChunks decodes the message from the recovered chunks provided. Legacy
+// shares include the message in a single chunk without length prefix.
+func decodeChunks(chunks []*big.Int, legacy bool, conf *Config) ([]byte, error) {
+	if legacy {
+		return chunks[0].Bytes(), nil
+	}
+	return chunksToMessage(chunks, conf.MaxMessageLen())
+}
+
+// checkShares checks that the decoded shares provided can be used together
+// with the configuration provided. There must be at least one share, every
+// share must have a different x coordinate and the same number of chunks, and
+// none of them can be nil. If the shares include metadata, they must belong to
+// the same set and epoch, and be generated with the prime number of the
+// configuration. Legacy shares can not be mixed with versioned ones, and the
+// blinding shares of the Pedersen commitments are rejected, because they do
+// not hide the message. It does not check if there are enough shares to
+// recover the message. Only the prime backend supports typed shares.
+func checkShares(shares []Share, conf *Config) error {
+	if conf.Backend != PrimeBackend || conf.Field != nil {
+		return ErrUnsupportedBackend
+	}
+	if len(shares) == 0 {
+		return ErrNotEnoughShares
+	}
+	first := shares[0]
+	xs := map[string]bool{}
+	for _, s := range shares {
+		if s.Index == nil || len(s.Values) == 0 || slices.Contains(s.Values, nil) {
+			return ErrInvalidShare
+		}
+		if s.Blinding {
+			return ErrBlindingShare
+		}
+		if xs[s.Index.String()] {
+			return ErrDuplicatedShare
 		}
-		xs = append(xs, x)
-		ys = append(ys, y)
-	}
-	// calculate the secret using the Lagrange interpolation, the secret is the
-	// first coefficient of the polynomial (x = 0)
-	secret := lagrangeInterpolation(xs, ys, conf.Prime, big.NewInt(0))
-	// decode the message from the secret y coord
-	return secret.Bytes(), nil
+		xs[s.Index.String()] = true
+		if len(s.Values) != len(first.Values) {
+			return ErrInvalidShare
+		}
+		if s.legacy() != first.legacy() {
+			return ErrShareSetMismatch
+		}
+		if s.legacy() {
+			continue
+		}
+		if !bytes.Equal(s.SetID, first.SetID) || s.Threshold != first.Threshold || s.Total != first.Total {
+			return ErrShareSetMismatch
+		}
+		if s.Epoch != first.Epoch {
+			return ErrEpochMismatch
+		}
+		if !bytes.Equal(s.PrimeID, conf.primeID()) {
+			return ErrPrimeMismatch
+		}
+	}
+	return nil
 }
@@ -2,6 +2,9 @@ package gosss
 
 import (
 	"bytes"
+	crand "crypto/rand"
+	"errors"
+	"math/big"
 	"math/rand"
 	"testing"
 )
@@ -41,3 +44,173 @@ func TestHideRecoverMessage(t *testing.T) {
 		t.Errorf("unexpected message: %s", message)
 	}
 }
+
+func TestHideRecoverLongMessage(t *testing.T) {
+	config := &Config{
+		Shares: 8,
+		Min:    5,
+	}
+	message := bytes.Repeat([]byte("a long message split in chunks "), 10)
+	totalShares, err := HideMessage(message, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(totalShares) != config.Shares {
+		t.Fatalf("unexpected number of shares: %d", len(totalShares))
+	}
+	recovered, err := RecoverMessage(totalShares[2:2+config.Min], config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(message, recovered) {
+		t.Errorf("unexpected message: %s", recovered)
+	}
+	// shares with a different number of chunks
+	shortShares, err := HideMessage(examplePrivateMessage, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mixed := append([]string{shortShares[0]}, totalShares[1:config.Min]...)
+	if _, err := RecoverMessage(mixed, config); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestHideRecoverLeadingZeros(t *testing.T) {
+	config := &Config{
+		Shares: 5,
+		Min:    3,
+	}
+	// random keys starting with zero bytes, including one of the exact size
+	// of a chunk and other only with zeros
+	key := make([]byte, 32)
+	if _, err := crand.Read(key[3:]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	messages := [][]byte{
+		key,
+		key[:len(DefaultPrime.Bytes())],
+		make([]byte, 16),
+		{0},
+		{},
+	}
+	for _, message := range messages {
+		totalShares, err := HideMessage(message, config)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		recovered, err := RecoverMessage(totalShares[:config.Min], config)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(message, recovered) {
+			t.Errorf("unexpected message: expected %x, got %x", message, recovered)
+		}
+	}
+}
+
+func TestRecoverMessageChecks(t *testing.T) {
+	config := &Config{
+		Shares: 5,
+		Min:    3,
+	}
+	shares, err := HideMessage(examplePrivateMessage, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	otherShares, err := HideMessage(examplePrivateMessage, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// not enough shares
+	if _, err := RecoverMessage(shares[:config.Min-1], nil); err != ErrNotEnoughShares {
+		t.Errorf("expected %v, got %v", ErrNotEnoughShares, err)
+	}
+	// duplicated shares
+	duplicated := []string{shares[0], shares[1], shares[1]}
+	if _, err := RecoverMessage(duplicated, nil); err != ErrDuplicatedShare {
+		t.Errorf("expected %v, got %v", ErrDuplicatedShare, err)
+	}
+	// shares of different sets
+	mixed := []string{shares[0], shares[1], otherShares[2]}
+	if _, err := RecoverMessage(mixed, nil); err != ErrShareSetMismatch {
+		t.Errorf("expected %v, got %v", ErrShareSetMismatch, err)
+	}
+	// different prime
+	otherPrime := &Config{Prime: big.NewInt(1000003)}
+	if _, err := RecoverMessage(shares[:config.Min], otherPrime); err != ErrPrimeMismatch {
+		t.Errorf("expected %v, got %v", ErrPrimeMismatch, err)
+	}
+}
+
+func TestRecoverLegacyMessage(t *testing.T) {
+	// generate the shares with the legacy format, where the message is encoded
+	// in a single chunk without length prefix
+	coeffs, err := calcCoeffs(big.NewInt(0).SetBytes(examplePrivateMessage), DefaultPrime, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	xs, ys := calcShares(coeffs, 4, DefaultPrime)
+	shares := []string{}
+	for i := range xs {
+		share, err := shareToStr(xs[i], ys[i])
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		shares = append(shares, share)
+	}
+	message, err := RecoverMessage(shares[1:], nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(message, examplePrivateMessage) {
+		t.Errorf("unexpected message: %s", message)
+	}
+}
+
+func TestRecoverMessageCorruptedShare(t *testing.T) {
+	config := &Config{
+		Shares: 5,
+		Min:    3,
+	}
+	shares, err := HideMessage(examplePrivateMessage, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the checksum error is returned even if the corrupted share can be
+	// decoded as a legacy share
+	position := 2
+	// flip a single hex digit of the chosen share
+	corrupted := []byte(shares[position])
+	if corrupted[20] == '0' {
+		corrupted[20] = '1'
+	} else {
+		corrupted[20] = '0'
+	}
+	shares[position] = string(corrupted)
+	_, err = RecoverMessage(shares, config)
+	if !errors.Is(err, ErrShareChecksum) {
+		t.Fatalf("expected %v, got %v", ErrShareChecksum, err)
+	}
+	var shareErr *ShareError
+	if !errors.As(err, &shareErr) || shareErr.Position != position {
+		t.Errorf("expected error of share %d, got %v", position, err)
+	}
+	// a checksum that makes the share a valid legacy point does not fall back
+	// to the legacy format
+	var share Share
+	if err := share.UnmarshalText([]byte(shares[0])); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := share.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b[len(b)-2], b[len(b)-1] = byte(len(b)-2-1), 1
+	if _, _, err := bytesToPoint(b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := share.UnmarshalBinary(b); err != ErrShareChecksum {
+		t.Errorf("expected %v, got %v", ErrShareChecksum, err)
+	}
+}
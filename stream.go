@@ -0,0 +1,226 @@
+package gosss
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+const (
+	// streamBlockSize is the number of bytes of the input hidden in every
+	// frame of the share streams, which bounds the memory used to split and
+	// combine them.
+	streamBlockSize = 64 << 10
+	// streamMaxFrameLen is the maximum length in bytes of a frame of a share
+	// stream, to avoid large allocations when the stream is corrupted.
+	streamMaxFrameLen = 16 << 20
+)
+
+// Splitter struct hides the data read from an io.Reader, writing a share
+// stream for every holder to its io.Writer, with bounded memory. The data is
+// read in blocks, every block is hidden with its own polynomials, and the
+// shares of every block are written as a frame of the stream of every holder:
+//
+//	seq | len(share) | share | ... | seq | 0
+//
+// where seq is the number of the frame encoded as a varint and share is the
+// binary encoding of the share of the block, with the same set identifier for
+// every frame. The stream ends with an empty frame, so a truncated stream can
+// be detected. It implements the io.ReaderFrom interface.
+type Splitter struct {
+	writers []io.Writer
+	conf    *Config
+}
+
+// NewSplitter returns a Splitter that writes the share streams to the writers
+// provided, one per holder, using the configuration provided. It returns an
+// error if the configuration is not valid or if the number of writers does
+// not match the number of shares of the configuration.
+func NewSplitter(writers []io.Writer, conf *Config) (*Splitter, error) {
+	// the prime number is selected for a full block, because the size of the
+	// data is not known in advance, and it is used for every block
+	conf, err := messageConfig(make([]byte, streamBlockSize), conf)
+	if err != nil {
+		return nil, err
+	}
+	if err := conf.ValidConfig(nil); err != nil {
+		return nil, err
+	}
+	if len(writers) != conf.Shares {
+		return nil, ErrConfigShares
+	}
+	return &Splitter{writers: writers, conf: conf}, nil
+}
+
+// ReadFrom reads the data from the reader provided until EOF and writes the
+// share streams to the writers of the splitter. It returns the number of
+// bytes read, or an error if the data cannot be read or hidden or if any
+// stream cannot be written.
+func (s *Splitter) ReadFrom(r io.Reader) (int64, error) {
+	setID, err := newSetID()
+	if err != nil {
+		return 0, err
+	}
+	block := make([]byte, streamBlockSize)
+	var read int64
+	for seq := uint64(0); ; seq++ {
+		n, err := io.ReadFull(r, block)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return read, err
+		}
+		read += int64(n)
+		if n == 0 {
+			// write the empty frame that ends every stream
+			return read, s.writeFrames(seq, nil)
+		}
+		shares, _, hideErr := hideMessage(block[:n], s.conf)
+		if hideErr != nil {
+			return read, hideErr
+		}
+		for i := range shares {
+			shares[i].SetID = setID
+		}
+		if err := s.writeFrames(seq, shares); err != nil {
+			return read, err
+		}
+	}
+}
+
+// writeFrames writes the frame with the number provided to the stream of
+// every holder, including its share, or an empty frame if no shares are
+// provided.
+func (s *Splitter) writeFrames(seq uint64, shares []Share) error {
+	for i, w := range s.writers {
+		frame := binary.AppendUvarint(nil, seq)
+		if shares == nil {
+			frame = binary.AppendUvarint(frame, 0)
+		} else {
+			b, err := shares[i].MarshalBinary()
+			if err != nil {
+				return err
+			}
+			frame = binary.AppendUvarint(frame, uint64(len(b)))
+			frame = append(frame, b...)
+		}
+		if _, err := w.Write(frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Combiner struct recovers the data hidden with a Splitter from the share
+// streams read from the io.Readers provided, at least as many as the
+// threshold of the set, and writes it to an io.Writer with bounded memory. It
+// implements the io.WriterTo interface.
+type Combiner struct {
+	readers []*bufio.Reader
+	conf    *Config
+}
+
+// NewCombiner returns a Combiner that reads the share streams from the
+// readers provided, using the configuration provided. If the configuration is
+// not provided, the prime number is taken from the shares. It returns an
+// error if no reader is provided.
+func NewCombiner(readers []io.Reader, conf *Config) (*Combiner, error) {
+	if len(readers) == 0 {
+		return nil, ErrNotEnoughShares
+	}
+	c := &Combiner{conf: conf}
+	for _, r := range readers {
+		c.readers = append(c.readers, bufio.NewReader(r))
+	}
+	return c, nil
+}
+
+// WriteTo reads the frames of every share stream, recovers the data of every
+// block with RecoverMessageShares and writes it to the writer provided, until
+// the empty frame that ends the streams. It returns the number of bytes
+// written or an error if the data cannot be recovered or written. If a stream
+// cannot be read, it ends before the empty frame or its frames are out of
+// order, it returns a ShareError with the position of the stream.
+func (c *Combiner) WriteTo(w io.Writer) (int64, error) {
+	var written int64
+	var setID []byte
+	shares := make([]Share, len(c.readers))
+	for seq := uint64(0); ; seq++ {
+		ended := 0
+		for i, r := range c.readers {
+			share, err := readFrame(r, seq)
+			if err != nil {
+				return written, &ShareError{Position: i, Err: err}
+			}
+			if share == nil {
+				ended++
+				continue
+			}
+			// every frame must belong to the same set of the first one
+			if setID == nil {
+				setID = share.SetID
+			} else if !bytes.Equal(share.SetID, setID) {
+				return written, &ShareError{Position: i, Err: ErrShareSetMismatch}
+			}
+			shares[i] = *share
+		}
+		if ended == len(c.readers) {
+			return written, nil
+		}
+		if ended > 0 {
+			return written, ErrTruncatedStream
+		}
+		block, err := RecoverMessageShares(shares, c.conf)
+		if err != nil {
+			return written, err
+		}
+		n, err := w.Write(block)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+}
+
+// readFrame reads the frame of a share stream with the number provided. It
+// returns the share of the frame, or nil if it is the empty frame that ends
+// the stream. It returns an error if the stream ends before the empty frame,
+// if the number of the frame does not match or if the share cannot be
+// decoded.
+func readFrame(r *bufio.Reader, seq uint64) (*Share, error) {
+	frameSeq, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, streamError(err)
+	}
+	if frameSeq != seq {
+		return nil, ErrInvalidShare
+	}
+	length, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, streamError(err)
+	}
+	if length == 0 {
+		return nil, nil
+	}
+	if length > streamMaxFrameLen {
+		return nil, ErrInvalidShare
+	}
+	b := make([]byte, length)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, streamError(err)
+	}
+	share := &Share{}
+	if err := share.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+// streamError returns ErrTruncatedStream if the error provided means that the
+// stream ended unexpectedly, or the error itself otherwise.
+func streamError(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrTruncatedStream
+	}
+	return err
+}
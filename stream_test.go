@@ -0,0 +1,90 @@
+package gosss
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestSplitterCombiner(t *testing.T) {
+	config := &Config{
+		Shares: 5,
+		Min:    3,
+	}
+	for _, size := range []int{0, 10, streamBlockSize, 3*streamBlockSize + 123} {
+		data := make([]byte, size)
+		if _, err := rand.Read(data); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		streams := make([]*bytes.Buffer, config.Shares)
+		writers := make([]io.Writer, config.Shares)
+		for i := range streams {
+			streams[i] = &bytes.Buffer{}
+			writers[i] = streams[i]
+		}
+		splitter, err := NewSplitter(writers, config)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		n, err := splitter.ReadFrom(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n != int64(size) {
+			t.Errorf("expected %d, got %d", size, n)
+		}
+		combiner, err := NewCombiner([]io.Reader{
+			bytes.NewReader(streams[4].Bytes()),
+			bytes.NewReader(streams[0].Bytes()),
+			bytes.NewReader(streams[2].Bytes()),
+		}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		recovered := &bytes.Buffer{}
+		if _, err := combiner.WriteTo(recovered); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(recovered.Bytes(), data) {
+			t.Errorf("unexpected data recovered with size %d", size)
+		}
+		if size == 0 {
+			continue
+		}
+		// not enough streams
+		combiner, _ = NewCombiner([]io.Reader{
+			bytes.NewReader(streams[1].Bytes()),
+			bytes.NewReader(streams[3].Bytes()),
+		}, nil)
+		if _, err := combiner.WriteTo(io.Discard); err != ErrNotEnoughShares {
+			t.Errorf("expected %v, got %v", ErrNotEnoughShares, err)
+		}
+		// truncated stream
+		truncated := streams[1].Bytes()[:streams[1].Len()-2]
+		combiner, _ = NewCombiner([]io.Reader{
+			bytes.NewReader(truncated),
+			bytes.NewReader(streams[2].Bytes()),
+			bytes.NewReader(streams[3].Bytes()),
+		}, nil)
+		_, err = combiner.WriteTo(io.Discard)
+		var shareErr *ShareError
+		if !errors.As(err, &shareErr) || shareErr.Position != 0 || shareErr.Err != ErrTruncatedStream {
+			t.Errorf("expected %v, got %v", ErrTruncatedStream, err)
+		}
+	}
+}
+
+func TestNewSplitter(t *testing.T) {
+	if _, err := NewSplitter(nil, nil); err != ErrRequiredConfig {
+		t.Errorf("expected %v, got %v", ErrRequiredConfig, err)
+	}
+	writers := []io.Writer{io.Discard, io.Discard}
+	if _, err := NewSplitter(writers, &Config{Shares: 3, Min: 2}); err != ErrConfigShares {
+		t.Errorf("expected %v, got %v", ErrConfigShares, err)
+	}
+	if _, err := NewCombiner(nil, nil); err != ErrNotEnoughShares {
+		t.Errorf("expected %v, got %v", ErrNotEnoughShares, err)
+	}
+}
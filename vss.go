@@ -0,0 +1,323 @@
+package gosss
+
+import (
+	"bytes"
+	"math/big"
+)
+
+// pedersenDomain is the data used to derive the second element of the group
+// for the Pedersen commitments, whose discrete logarithm is unknown.
+var pedersenDomain = []byte("gosss pedersen commitments")
+
+// Commitments struct contains the commitments to the coefficients of the
+// polynomial of every chunk of a message, published by the dealer when the
+// message is hidden with a verifiable secret sharing mode. Each commitment is
+// the encoding of an element of the group of the configuration. It also
+// includes the identifier of the set of shares it belongs to.
+type Commitments struct {
+	SetID  []byte
+	Chunks [][][]byte
+}
+
+// HideVerifiableMessage generates the shares of the message as
+// HideMessageShares does, and the Feldman commitments to the coefficients of
+// the polynomial of every chunk, which are the generator of the group of the
+// configuration multiplied by every coefficient (g^a_i). The commitments can
+// be published so every holder can verify its share with VerifyShare without
+// the help of the dealer. The order of the group of the configuration must be
+// the prime number, if the group is not defined and the default prime is
+// used, the bn254 G1 group is used. It returns an error if the message cannot
+// be encoded or if there is no valid group for the prime number. The
+// commitments reveal the generator multiplied by the message, so this mode
+// should not be used to hide low entropy messages. The default groups are not
+// constant time, so the time of the commitments leaks information about the
+// message to an attacker that can measure it, see Group.
+func HideVerifiableMessage(message []byte, conf *Config) ([]Share, *Commitments, error) {
+	conf, err := messageConfig(message, conf)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the prime number must be checked before the group, because it is not
+	// defined if its name is not registered
+	if err := conf.ValidPrime(); err != nil {
+		return nil, nil, err
+	}
+	group, err := conf.group()
+	if err != nil {
+		return nil, nil, err
+	}
+	shares, chunksCoeffs, err := hideMessage(message, conf)
+	if err != nil {
+		return nil, nil, err
+	}
+	commitments := &Commitments{
+		SetID:  shares[0].SetID,
+		Chunks: make([][][]byte, len(chunksCoeffs)),
+	}
+	for i, coeffs := range chunksCoeffs {
+		commitments.Chunks[i] = commitPolynomial(group, coeffs)
+	}
+	return shares, commitments, nil
+}
+
+// VerifyShare verifies that the share provided is a point of the polynomials
+// committed with the Feldman commitments provided. For the point of every
+// chunk, it checks that the generator of the group multiplied by the y
+// coordinate matches the commitments evaluated at the x coordinate:
+//
+//	g^y = C_0 * C_1^x * C_2^(x^2) * ... * C_k^(x^k)
+//
+// It uses the group of the configuration provided, if the configuration is not
+// provided, the default prime and the bn254 G1 group are used. It returns an
+// error if the share does not belong to the same set of the commitments, if
+// the commitments are not valid, including when the number of commitments of
+// any chunk does not match the threshold of the share, or if the share does
+// not match them. For legacy shares, the threshold is the minimum number of
+// shares of the configuration.
+func VerifyShare(share Share, commitments *Commitments, conf *Config) error {
+	conf = sharesConfig([]Share{share}, conf)
+	if err := conf.ValidPrime(); err != nil {
+		return err
+	}
+	group, err := conf.group()
+	if err != nil {
+		return err
+	}
+	if err := checkCommitments(share, commitments, conf); err != nil {
+		return err
+	}
+	for i, y := range share.Values {
+		expected, err := evalCommitments(group, commitments.Chunks[i], share.Index)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(group.ScalarBaseMul(y), expected) {
+			return ErrShareNotVerified
+		}
+	}
+	return nil
+}
+
+// HidePedersenMessage generates the shares of the message as
+// HideMessageShares does, and the Pedersen commitments to the coefficients of
+// the polynomial of every chunk. For every chunk, it samples a second random
+// blinding polynomial and commits to both polynomials together, adding the
+// generator of the group multiplied by every coefficient of the chunk
+// polynomial and a second element of the group, whose discrete logarithm is
+// unknown, multiplied by every coefficient of the blinding polynomial
+// (g^a_i * h^b_i). Unlike Feldman commitments, these commitments do not
+// reveal any information about the message, even if it has low entropy. It
+// returns the shares, the blinding shares, which have the same index of the
+// share of every holder and the points of the blinding polynomials, and the
+// commitments. Every holder needs its share and its blinding share to verify
+// them with VerifyPedersenShare. The blinding shares are not needed to
+// recover the message. It returns an error if the message cannot be encoded
+// or if there is no valid group for the prime number. As HideVerifiableMessage,
+// it must run where the time of the group operations can not be measured.
+func HidePedersenMessage(message []byte, conf *Config) ([]Share, []Share, *Commitments, error) {
+	conf, err := messageConfig(message, conf)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// the prime number must be checked before the group, because it is not
+	// defined if its name is not registered
+	if err := conf.ValidPrime(); err != nil {
+		return nil, nil, nil, err
+	}
+	group, err := conf.group()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	shares, chunksCoeffs, err := hideMessage(message, conf)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	blindings, blindingsCoeffs, err := blindingShares(shares, conf)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	h := group.HashToElement(pedersenDomain)
+	commitments := &Commitments{
+		SetID:  shares[0].SetID,
+		Chunks: make([][][]byte, len(chunksCoeffs)),
+	}
+	for i, coeffs := range chunksCoeffs {
+		if commitments.Chunks[i], err = commitPedersenPolynomial(group, h, coeffs, blindingsCoeffs[i]); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return shares, blindings, commitments, nil
+}
+
+// VerifyPedersenShare verifies that the share and the blinding share provided
+// are points of the polynomials committed with the Pedersen commitments
+// provided. For the points of every chunk, it checks that the sum of the
+// generator of the group multiplied by the y coordinate of the share and the
+// second element of the group multiplied by the y coordinate of the blinding
+// share matches the commitments evaluated at the x coordinate:
+//
+//	g^y * h^y' = C_0 * C_1^x * C_2^(x^2) * ... * C_k^(x^k)
+//
+// It uses the group of the configuration provided, if the configuration is not
+// provided, the default prime and the bn254 G1 group are used. It returns an
+// error if the share or the blinding share do not belong to the same set of
+// the commitments, if they have different indexes, if the share is a blinding
+// share or the blinding share is not, if the commitments are not valid or if
+// the shares do not match them.
+func VerifyPedersenShare(share, blinding Share, commitments *Commitments, conf *Config) error {
+	conf = sharesConfig([]Share{share}, conf)
+	if err := conf.ValidPrime(); err != nil {
+		return err
+	}
+	group, err := conf.group()
+	if err != nil {
+		return err
+	}
+	if err := checkCommitments(share, commitments, conf); err != nil {
+		return err
+	}
+	if err := checkCommitments(blinding, commitments, conf); err != nil {
+		return err
+	}
+	if share.Index.Cmp(blinding.Index) != 0 || share.Blinding || !blinding.Blinding {
+		return ErrInvalidShare
+	}
+	h := group.HashToElement(pedersenDomain)
+	for i, y := range share.Values {
+		expected, err := evalCommitments(group, commitments.Chunks[i], share.Index)
+		if err != nil {
+			return err
+		}
+		hy, err := group.ScalarMul(h, blinding.Values[i])
+		if err != nil {
+			return err
+		}
+		result, err := group.Add(group.ScalarBaseMul(y), hy)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(result, expected) {
+			return ErrShareNotVerified
+		}
+	}
+	return nil
+}
+
+// checkCommitments checks that the share and the commitments provided can be
+// verified together. The share must have an index and the same number of
+// chunks of the commitments, and if both include the set identifier, it must
+// be the same. Every chunk must have exactly as many commitments as the
+// threshold of the share, so the polynomials committed have the degree that
+// the threshold requires and any quorum of valid shares recovers the same
+// message. Legacy shares have no threshold, so the minimum number of shares of
+// the configuration is required instead.
+func checkCommitments(share Share, commitments *Commitments, conf *Config) error {
+	if commitments == nil || len(commitments.Chunks) == 0 {
+		return ErrInvalidCommitments
+	}
+	if share.Index == nil || len(share.Values) != len(commitments.Chunks) {
+		return ErrInvalidShare
+	}
+	threshold := share.Threshold
+	if threshold == 0 {
+		threshold = conf.Min
+	}
+	if threshold < MinMinShares {
+		return ErrInvalidCommitments
+	}
+	for i, chunk := range commitments.Chunks {
+		if len(chunk) != threshold {
+			return ErrInvalidCommitments
+		}
+		if share.Values[i] == nil {
+			return ErrInvalidShare
+		}
+	}
+	if share.SetID != nil && commitments.SetID != nil && !bytes.Equal(share.SetID, commitments.SetID) {
+		return ErrShareSetMismatch
+	}
+	return nil
+}
+
+// commitPolynomial returns the Feldman commitments to the coefficients of a
+// polynomial, which are the generator of the group multiplied by every
+// coefficient.
+func commitPolynomial(group Group, coeffs []*big.Int) [][]byte {
+	commitments := make([][]byte, len(coeffs))
+	for i, coeff := range coeffs {
+		commitments[i] = group.ScalarBaseMul(coeff)
+	}
+	return commitments
+}
+
+// commitPedersenPolynomial returns the Pedersen commitments to the
+// coefficients of a polynomial and its blinding polynomial, which are the sum
+// of the generator of the group multiplied by every coefficient and the
+// element h multiplied by the coefficient of the blinding polynomial of the
+// same degree. It returns an error if h is not a valid element of the group.
+func commitPedersenPolynomial(group Group, h []byte, coeffs, blindingCoeffs []*big.Int) ([][]byte, error) {
+	commitments := make([][]byte, len(coeffs))
+	for i, coeff := range coeffs {
+		hb, err := group.ScalarMul(h, blindingCoeffs[i])
+		if err != nil {
+			return nil, err
+		}
+		if commitments[i], err = group.Add(group.ScalarBaseMul(coeff), hb); err != nil {
+			return nil, err
+		}
+	}
+	return commitments, nil
+}
+
+// blindingShares generates a random blinding polynomial for every chunk of the
+// shares provided, with the same degree of the chunk polynomials, and returns
+// the blinding shares, which have the same metadata and index of the shares
+// provided but are marked as blinding shares and have the points of the
+// blinding polynomials, and the coefficients of the blinding polynomials. It
+// returns an error if the random coefficients cannot be generated.
+func blindingShares(shares []Share, conf *Config) ([]Share, [][]*big.Int, error) {
+	nchunks := len(shares[0].Values)
+	blindings := make([]Share, len(shares))
+	for i, share := range shares {
+		blindings[i] = share
+		blindings[i].Blinding = true
+		blindings[i].Values = make([]*big.Int, nchunks)
+	}
+	chunksCoeffs := make([][]*big.Int, nchunks)
+	for j := range chunksCoeffs {
+		secret, err := randFieldElement(conf.Prime)
+		if err != nil {
+			return nil, nil, err
+		}
+		if chunksCoeffs[j], err = calcCoeffs(secret, conf.Prime, conf.Min); err != nil {
+			return nil, nil, err
+		}
+		for i, share := range shares {
+			blindings[i].Values[j] = solvePolynomial(chunksCoeffs[j], share.Index, conf.Prime)
+		}
+	}
+	return blindings, chunksCoeffs, nil
+}
+
+// evalCommitments evaluates the commitments to the coefficients of a
+// polynomial at x, which results in the commitment to the evaluation of the
+// polynomial at x. It uses the Horner's method in the group, like
+// solvePolynomial does in the finite field. It returns an error if any
+// commitment is not a valid element of the group.
+func evalCommitments(group Group, commitments [][]byte, x *big.Int) ([]byte, error) {
+	accum := commitments[len(commitments)-1]
+	for i := len(commitments) - 2; i >= 0; i-- {
+		var err error
+		if accum, err = group.ScalarMul(accum, x); err != nil {
+			return nil, err
+		}
+		if accum, err = group.Add(accum, commitments[i]); err != nil {
+			return nil, err
+		}
+	}
+	// check the last commitment is valid if it is the only one
+	if len(commitments) == 1 {
+		return group.ScalarMul(accum, big.NewInt(1))
+	}
+	return accum, nil
+}
@@ -0,0 +1,201 @@
+package gosss
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestHideVerifiableMessage(t *testing.T) {
+	config := &Config{
+		Shares: 5,
+		Min:    3,
+	}
+	shares, commitments, err := HideVerifiableMessage(examplePrivateMessage, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commitments.Chunks) != len(shares[0].Values) || len(commitments.Chunks[0]) != config.Min {
+		t.Fatalf("unexpected number of commitments")
+	}
+	for _, share := range shares {
+		if err := VerifyShare(share, commitments, nil); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	message, err := RecoverMessageShares(shares[:config.Min], config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(message, examplePrivateMessage) {
+		t.Errorf("unexpected message: %s", message)
+	}
+	// tampered share
+	tampered := shares[0]
+	tampered.Values = []*big.Int{new(big.Int).Add(shares[0].Values[0], big.NewInt(1))}
+	if err := VerifyShare(tampered, commitments, nil); err != ErrShareNotVerified {
+		t.Errorf("expected %v, got %v", ErrShareNotVerified, err)
+	}
+	// share of other set
+	otherShares, _, err := HideVerifiableMessage(examplePrivateMessage, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := VerifyShare(otherShares[0], commitments, nil); err != ErrShareSetMismatch {
+		t.Errorf("expected %v, got %v", ErrShareSetMismatch, err)
+	}
+	// invalid commitments
+	if err := VerifyShare(shares[0], &Commitments{}, nil); err != ErrInvalidCommitments {
+		t.Errorf("expected %v, got %v", ErrInvalidCommitments, err)
+	}
+}
+
+func TestVerifyShareCheatingDealer(t *testing.T) {
+	// the dealer commits to polynomials of degree 3 but labels the shares with
+	// threshold 3, so no 3 shares recover the message
+	shares, commitments, err := HideVerifiableMessage(examplePrivateMessage, &Config{Shares: 5, Min: 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, share := range shares {
+		share.Threshold = 3
+		if err := VerifyShare(share, commitments, nil); err != ErrInvalidCommitments {
+			t.Errorf("expected %v, got %v", ErrInvalidCommitments, err)
+		}
+	}
+	// legacy shares have no threshold, so the configuration must provide it
+	legacy := shares[0]
+	legacy.SetID, legacy.PrimeID, legacy.Threshold, legacy.Total = nil, nil, 0, 0
+	if err := VerifyShare(legacy, commitments, nil); err != ErrInvalidCommitments {
+		t.Errorf("expected %v, got %v", ErrInvalidCommitments, err)
+	}
+	if err := VerifyShare(legacy, commitments, &Config{Min: 3}); err != ErrInvalidCommitments {
+		t.Errorf("expected %v, got %v", ErrInvalidCommitments, err)
+	}
+	if err := VerifyShare(legacy, commitments, &Config{Min: 4}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestHideVerifiableMessageSchnorrGroup(t *testing.T) {
+	prime := big.NewInt(1019)
+	group, err := NewSchnorrGroup(big.NewInt(2039), prime, big.NewInt(4))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	config := &Config{
+		Shares: 4,
+		Min:    2,
+		Prime:  prime,
+		Group:  group,
+	}
+	shares, commitments, err := HideVerifiableMessage([]byte("abc"), config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, share := range shares {
+		if err := VerifyShare(share, commitments, config); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	// the group order does not match the prime
+	if _, _, err := HideVerifiableMessage([]byte("abc"), &Config{Shares: 4, Min: 2, Group: group}); err != ErrConfigGroupOrder {
+		t.Errorf("expected %v, got %v", ErrConfigGroupOrder, err)
+	}
+	// no group for the prime
+	if _, _, err := HideVerifiableMessage([]byte("abc"), &Config{Shares: 4, Min: 2, Prime: prime}); err != ErrConfigNoGroup {
+		t.Errorf("expected %v, got %v", ErrConfigNoGroup, err)
+	}
+}
+
+func TestHidePedersenMessage(t *testing.T) {
+	config := &Config{
+		Shares: 5,
+		Min:    3,
+	}
+	shares, blindings, commitments, err := HidePedersenMessage(examplePrivateMessage, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(blindings) != len(shares) {
+		t.Fatalf("unexpected number of blinding shares: %d", len(blindings))
+	}
+	for i := range shares {
+		if err := VerifyPedersenShare(shares[i], blindings[i], commitments, nil); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	message, err := RecoverMessageShares(shares[:config.Min], config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(message, examplePrivateMessage) {
+		t.Errorf("unexpected message: %s", message)
+	}
+	// the commitments do not match the Feldman commitments
+	if err := VerifyShare(shares[0], commitments, nil); err != ErrShareNotVerified {
+		t.Errorf("expected %v, got %v", ErrShareNotVerified, err)
+	}
+	// blinding share of other holder
+	if err := VerifyPedersenShare(shares[0], blindings[1], commitments, nil); err != ErrInvalidShare {
+		t.Errorf("expected %v, got %v", ErrInvalidShare, err)
+	}
+	// tampered blinding share
+	tampered := blindings[0]
+	tampered.Values = []*big.Int{new(big.Int).Add(blindings[0].Values[0], big.NewInt(1))}
+	if err := VerifyPedersenShare(shares[0], tampered, commitments, nil); err != ErrShareNotVerified {
+		t.Errorf("expected %v, got %v", ErrShareNotVerified, err)
+	}
+	// the blinding shares are marked, also once encoded, so they can not be
+	// swapped with the shares or used to recover the message
+	if err := VerifyPedersenShare(blindings[0], shares[0], commitments, nil); err != ErrInvalidShare {
+		t.Errorf("expected %v, got %v", ErrInvalidShare, err)
+	}
+	var decoded Share
+	if err := decoded.UnmarshalText([]byte(blindings[1].String())); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := RecoverMessageShares([]Share{shares[0], decoded, shares[2]}, config); err != ErrBlindingShare {
+		t.Errorf("expected %v, got %v", ErrBlindingShare, err)
+	}
+}
+
+func TestVerifyPedersenShareCheatingDealer(t *testing.T) {
+	// the dealer commits to polynomials of degree 3 but labels the shares with
+	// threshold 3
+	shares, blindings, commitments, err := HidePedersenMessage(examplePrivateMessage, &Config{Shares: 5, Min: 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := range shares {
+		share, blinding := shares[i], blindings[i]
+		share.Threshold, blinding.Threshold = 3, 3
+		if err := VerifyPedersenShare(share, blinding, commitments, nil); err != ErrInvalidCommitments {
+			t.Errorf("expected %v, got %v", ErrInvalidCommitments, err)
+		}
+	}
+}
+
+func TestVerifiableMessageUnknownPrimeName(t *testing.T) {
+	config := &Config{Shares: 5, Min: 3, PrimeName: "unknown"}
+	if _, _, err := HideVerifiableMessage(examplePrivateMessage, config); err != ErrConfigPrimeName {
+		t.Errorf("expected %v, got %v", ErrConfigPrimeName, err)
+	}
+	config = &Config{Shares: 5, Min: 3, PrimeName: "unknown"}
+	if _, _, _, err := HidePedersenMessage(examplePrivateMessage, config); err != ErrConfigPrimeName {
+		t.Errorf("expected %v, got %v", ErrConfigPrimeName, err)
+	}
+	// a share with a prime name that is not registered by the verifier
+	shares, blindings, commitments, err := HidePedersenMessage(examplePrivateMessage, &Config{Shares: 5, Min: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	share, blinding := shares[0], blindings[0]
+	share.PrimeName, blinding.PrimeName = "unknown", "unknown"
+	if err := VerifyShare(share, commitments, nil); err != ErrConfigPrimeName {
+		t.Errorf("expected %v, got %v", ErrConfigPrimeName, err)
+	}
+	if err := VerifyPedersenShare(share, blinding, commitments, nil); err != ErrConfigPrimeName {
+		t.Errorf("expected %v, got %v", ErrConfigPrimeName, err)
+	}
+}
@@ -4,8 +4,10 @@ const app = createApp({
     data() {
         return {
             message: "",
-            maxLength: 0,
+            chunkLength: 0,
             prime: 0n,
+            primeNames: [],
+            primeName: "",
             sharesCount: 3,
             threshold: 2,
             shares: "",
@@ -17,13 +19,27 @@ const app = createApp({
     async created() {
         await this.setupWebAssembly();
         this.prime = GoSSS.defaultPrime;
-        this.maxLength = this.getMaxLength();
+        this.primeNames = GoSSS.primeNames;
+        // the default prime is registered as bn254
+        this.primeName = this.primeNames.includes("bn254") ? "bn254" : "custom";
+        this.chunkLength = this.getChunkLength();
     },
     computed: {
         messageLength() {
             const encoder = new TextEncoder();
             return encoder.encode(this.message).length;
         },
+        selectedPrime() {
+            if (this.primeName !== "custom") {
+                return this.primeName;
+            }
+            return BigInt(this.prime).toString();
+        },
+    },
+    watch: {
+        selectedPrime() {
+            this.chunkLength = this.getChunkLength();
+        },
     },
     template: `
         <div style="width: 90%; max-width: 800px; margin: 50px auto;" class="is-shadowed is-rounded has-p-12">
@@ -39,16 +55,20 @@ const app = createApp({
                 <button class="button has-m-2 has-w-full" :class="{'is-normal': currentTab != 'recover'}" @click="currentTab = 'recover'">Recover</button>
             </div>
             <div class="has-mt-6 has-mb-6">
-                <label class="label has-mb-2">Enter a large prime number</label>
-                <input type="number" class="input" v-model="prime" placeholder="Enter a prime number">
-                <small>Large prime numbers will be able to hide more information.</small>
+                <label class="label has-mb-2">Select a prime number</label>
+                <select class="select has-mb-2" v-model="primeName">
+                    <option v-for="name in primeNames" :value="name">{{ name }}</option>
+                    <option value="custom">custom</option>
+                </select>
+                <input type="number" class="input" v-model="prime" placeholder="Enter a prime number" v-show="primeName === 'custom'">
+                <small>Large prime numbers split the message into fewer chunks.</small>
             </div>
             <div v-show="currentTab === 'hide'">
                 <h3 class="has-mt-6 has-mb-8">Hide a message</h3>
                 <div class="has-mb-4">
                     <label class="label has-mb-2">Enter the message to hide</label>
-                    <textarea class="textarea" v-model="message" @input="validMessage" placeholder="Enter your message" rows="6"></textarea>
-                    <small>Bytes length: {{ messageLength }}/{{ maxLength }}</small>
+                    <textarea class="textarea" v-model="message" placeholder="Enter your message" rows="6"></textarea>
+                    <small>Bytes length: {{ messageLength }}, hidden in chunks of {{ chunkLength }} bytes</small>
                 </div>
                 <div class="is-flex has-direction-row has-justify-center has-mb-4">
                     <div class="has-w-full" v-show="message">
@@ -87,7 +107,7 @@ const app = createApp({
             go.run(result.instance);
         },
         hideMessage() {
-            const rawResult = GoSSS.hide(this.message, this.sharesCount, this.threshold, BigInt(this.prime).toString());
+            const rawResult = GoSSS.hide(this.message, this.sharesCount, this.threshold, this.selectedPrime);
             const result = JSON.parse(rawResult);
             if (!result.error) {
                 this.hide_result = result.data.join("\n");
@@ -97,7 +117,7 @@ const app = createApp({
         },
         recoverMessage() {
             const shares = JSON.stringify(this.shares.split("\n"));
-            const rawResult = GoSSS.recover(shares, BigInt(this.prime).toString());
+            const rawResult = GoSSS.recover(shares, this.selectedPrime);
             const result = JSON.parse(rawResult);
             if (!result.error) {
                 this.recovered_message = window.atob(result.data);
@@ -105,8 +125,8 @@ const app = createApp({
                 alert(`Error recovering message: ${result.error}`);
             }
         },
-        getMaxLength() {
-            const rawResult = GoSSS.maxLength(this.prime);
+        getChunkLength() {
+            const rawResult = GoSSS.maxLength(this.selectedPrime);
             const result = JSON.parse(rawResult);
             if (!result.error) {
                 return parseInt(result.data);
@@ -114,11 +134,6 @@ const app = createApp({
                 alert(`Error hiding message: ${result.error}`);
             }
         },
-        validMessage() {
-            if (this.messageLength > this.maxLength) {
-                this.message = this.message.slice(0, this.maxLength);
-            }
-        }
     },
 });
 